@@ -5,6 +5,7 @@ const (
 	FailedToLoadConfigErr = "failed to load configuration: %v"
 	FailedToGetConfigInfoErr = "failed to get configuration info: %v"
 	FailedToRunApplicationErr = "failed to run application: %v"
+	FailedToRunCommandErr = "failed to run %s command: %v"
 )
 
 const (
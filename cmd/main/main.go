@@ -18,6 +18,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	if cfg.ServesStdio() {
+		logger.SetOutput(os.Stderr)
+	}
 	logger.SetDebugMode(cfg.Debug)
 	if cfg.Debug {
 		if cfgString, err := cfg.GetConfigInfo(); err != nil {
@@ -27,11 +30,21 @@ func main() {
 		}
 	}
 
+	if cfg.Command != "" {
+		if err := app.RunCommand(cfg); err != nil {
+			logger.Error(fmt.Sprintf(FailedToRunCommandErr, cfg.Command, err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	application := app.New(cfg)
 	if err := application.Run(); err != nil {
 		logger.Error(fmt.Sprintf(FailedToRunApplicationErr, err))
 		os.Exit(1)
 	}
 
-	logger.Info(CLIExitedSuccessfullyMsg)
+	if !application.IsOneShot() {
+		logger.Info(CLIExitedSuccessfullyMsg)
+	}
 }
@@ -6,3 +6,5 @@ require (
 	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
 )
+
+require gopkg.in/yaml.v3 v3.0.1
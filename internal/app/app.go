@@ -34,32 +34,58 @@ func New(cfg *config.Config) *App {
 }
 
 func (app *App) Run() error {
-	// Run app
+	// Run app, either as an interactive chat or as a one-shot prompt
 	app.handleShutdown()
 
+	prompt, err := cli.GetOneShotPrompt(app.config.Prompt)
+	if err != nil {
+		return err
+	}
+	app.oneShot = prompt != ""
+
 	if err := app.oaiClient.Connect(app.ctx); err != nil {
 		appErr := *errorhandler.NewAppError(errorhandler.ErrorLevel, fmt.Sprintf(AppFailedToConnectToOAIErr, err), err)
 		app.errorHandler.HandleError(appErr)
 		return err
 	}
+	defer app.disconnect()
+
+	if app.config.Resume != "" {
+		if appErr := app.oaiClient.ResumeSession(app.ctx, app.config.Resume); appErr != nil {
+			return appErr.Error
+		}
+	}
+
+	if app.oneShot {
+		return app.cli.RunOneShot(app.ctx, prompt)
+	}
 
 	app.cli.Run(app.ctx, app.cancel)
 	return nil
 }
 
+func (app *App) IsOneShot() bool {
+	// Return if the app runs a single non-interactive prompt
+	return app.oneShot
+}
+
 func (app *App) handleShutdown() {
-	// Handle app shutdown
+	// Handle app shutdown, Ctrl+C (SIGINT) is handled by the cli to cancel in-flight responses
 	signalChannel := make(chan os.Signal, 1)
-	signal.Notify(signalChannel, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(signalChannel, syscall.SIGTERM)
 
 	go func() {
 		<-signalChannel
 		logger.Debug(AppShutdownMsg)
 		app.cancel()
-
-		if err := app.oaiClient.Disconnect(); err != nil {
-			appErr := *errorhandler.NewAppError(errorhandler.ErrorLevel, fmt.Sprintf(AppFailedToDisconnectFromOAIErr, err), err)
-			app.errorHandler.HandleError(appErr)
-		}
+		app.disconnect()
 	}()
 }
+
+func (app *App) disconnect() {
+	// Disconnect from OpenAI
+	if err := app.oaiClient.Disconnect(); err != nil {
+		appErr := *errorhandler.NewAppError(errorhandler.ErrorLevel, fmt.Sprintf(AppFailedToDisconnectFromOAIErr, err), err)
+		app.errorHandler.HandleError(appErr)
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/export"
+	"RTGPTGoCLI/internal/functions/handler"
+	"RTGPTGoCLI/internal/mcp"
+	"RTGPTGoCLI/internal/sessions"
+	"RTGPTGoCLI/pkg/logger"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func RunCommand(cfg *config.Config) error {
+	// Run a subcommand given after the flags
+	switch cfg.Command {
+	case config.ExportCommand:
+		return runExportCommand(cfg)
+	case config.MCPCommand:
+		return runMCPCommand(cfg)
+	default:
+		return fmt.Errorf(AppUnknownCommandErr, cfg.Command)
+	}
+}
+
+func runExportCommand(cfg *config.Config) error {
+	// Export a saved session: export <session-id> <format> <path>
+	if len(cfg.CommandArgs) != 3 {
+		return fmt.Errorf(AppExportUsageErr)
+	}
+	sessionID, format, path := cfg.CommandArgs[0], cfg.CommandArgs[1], cfg.CommandArgs[2]
+
+	session, err := sessions.NewStore(cfg.GetSessionsDir()).Load(sessionID)
+	if err != nil {
+		return err
+	}
+
+	return export.WriteFile(session, format, path)
+}
+
+func runMCPCommand(cfg *config.Config) error {
+	// Serve the registered functions over MCP stdio: mcp serve
+	if len(cfg.CommandArgs) != 1 || cfg.CommandArgs[0] != config.MCPServeSubcommand {
+		return fmt.Errorf(AppMCPUsageErr)
+	}
+
+	functionHandler := handler.NewHandler(cfg)
+	defer functionHandler.Close()
+	if appErr := functionHandler.LoadFunctions(); appErr != nil {
+		logger.Warning(appErr.Message)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return mcp.NewServer(functionHandler, os.Stdin, os.Stdout).Serve(ctx)
+}
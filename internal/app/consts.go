@@ -5,6 +5,9 @@ const (
 	AppFailedToConnectToOAIErr = "Failed to connect to OpenAI: %v"
 	AppFailedToDisconnectFromOAIErr = "Failed to disconnect from OpenAI: %v"
 	AppErrorClosingOAIConnErr = "Error closing OAI client: %v"
+	AppUnknownCommandErr = "unknown command: %s"
+	AppExportUsageErr = "usage: export <session-id> <markdown|html|json> <path>"
+	AppMCPUsageErr = "usage: mcp serve"
 )
 
 const (
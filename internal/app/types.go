@@ -16,4 +16,5 @@ type App struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 	errorHandler *errorhandler.ErrorHandler
+	oneShot   bool
 }
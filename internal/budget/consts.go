@@ -0,0 +1,40 @@
+package budget
+
+import "os"
+
+const (
+	// Ledger constants
+	LedgerDateLayout = "2006-01-02"
+	LedgerDirPermissions  os.FileMode = 0o700
+	LedgerFilePermissions os.FileMode = 0o600
+	LedgerLockSuffix = ".lock"
+	LedgerTempPattern = ".*.tmp"
+)
+
+const (
+	// Budget levels, each level is reported once per budget and period
+	BudgetWithinLevel = iota
+	BudgetWarningLevel
+	BudgetReachedLevel
+)
+
+const (
+	// Budget texts
+	BudgetTokensText = "%d of %d tokens"
+	BudgetCostText = "%.4f of %.4f %s"
+	BudgetUsedTokensText = "%d tokens"
+	BudgetUsedCostText = "%.4f %s"
+	BudgetNoLimitText = "no limit"
+	BudgetPartsSeparator = ", "
+	BudgetPeriodScopeText = "%s %s"
+	BudgetWarningText = "%s budget is %d%% used: %s"
+	BudgetReachedText = "%s budget reached: %s, new prompts are refused until /budget override"
+)
+
+const (
+	// Errors
+	BudgetExceededErr = "%s budget exceeded: %s, use /budget override to keep sending prompts"
+	LedgerReadErr = "failed to read usage ledger %s: %v"
+	LedgerWriteErr = "failed to write usage ledger %s: %v"
+	LedgerLockErr = "failed to lock usage ledger %s: %v"
+)
@@ -0,0 +1,146 @@
+package budget
+
+import (
+	"RTGPTGoCLI/internal/sessions"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func NewLedger(path string) *Ledger {
+	// Create usage ledger stored at the given path
+	return &Ledger{path: path}
+}
+
+func (ledger *Ledger) Add(profile string, date time.Time, response sessions.ResponseUsage) error {
+	// Add the usage of a response to the profile total of its date, re-reading the file under a file lock so concurrent runs are kept
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	unlock, err := ledger.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ledgerFile, err := ledger.read()
+	if err != nil {
+		return err
+	}
+
+	days, exists := ledgerFile.Profiles[profile]
+	if !exists {
+		days = map[string]sessions.Usage{}
+		ledgerFile.Profiles[profile] = days
+	}
+	dayKey := date.Format(LedgerDateLayout)
+	usage := days[dayKey]
+	usage.Add(response)
+	days[dayKey] = usage
+
+	return ledger.write(ledgerFile)
+}
+
+func (ledger *Ledger) GetDay(profile string, date time.Time) (sessions.Usage, error) {
+	// Return the usage of a profile on a date
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	ledgerFile, err := ledger.read()
+	if err != nil {
+		return sessions.Usage{}, err
+	}
+	return ledgerFile.Profiles[profile][date.Format(LedgerDateLayout)], nil
+}
+
+func (ledger *Ledger) GetProfile(profile string) (sessions.Usage, error) {
+	// Return the total usage of a profile over every date
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	ledgerFile, err := ledger.read()
+	if err != nil {
+		return sessions.Usage{}, err
+	}
+
+	total := sessions.Usage{}
+	for _, usage := range ledgerFile.Profiles[profile] {
+		total.Merge(usage)
+	}
+	return total, nil
+}
+
+func (ledger *Ledger) read() (*LedgerFile, error) {
+	// Read the ledger file, an empty ledger when it doesn't exist yet
+	ledgerFile := &LedgerFile{}
+	jsonBytes, err := os.ReadFile(ledger.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf(LedgerReadErr, ledger.path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(jsonBytes, ledgerFile); err != nil {
+			return nil, fmt.Errorf(LedgerReadErr, ledger.path, err)
+		}
+	}
+
+	if ledgerFile.Profiles == nil {
+		ledgerFile.Profiles = map[string]map[string]sessions.Usage{}
+	}
+	return ledgerFile, nil
+}
+
+func (ledger *Ledger) write(ledgerFile *LedgerFile) error {
+	// Write the ledger file, replacing the previous version atomically
+	if err := os.MkdirAll(filepath.Dir(ledger.path), LedgerDirPermissions); err != nil {
+		return fmt.Errorf(LedgerWriteErr, ledger.path, err)
+	}
+
+	jsonBytes, err := json.MarshalIndent(ledgerFile, "", "  ")
+	if err != nil {
+		return fmt.Errorf(LedgerWriteErr, ledger.path, err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(ledger.path), filepath.Base(ledger.path)+LedgerTempPattern)
+	if err != nil {
+		return fmt.Errorf(LedgerWriteErr, ledger.path, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(jsonBytes)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), LedgerFilePermissions)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), ledger.path)
+	}
+	if err != nil {
+		return fmt.Errorf(LedgerWriteErr, ledger.path, err)
+	}
+	return nil
+}
+
+func (ledger *Ledger) lock() (func(), error) {
+	// Take the file lock of the ledger, shared by every run using the same data directory
+	if err := os.MkdirAll(filepath.Dir(ledger.path), LedgerDirPermissions); err != nil {
+		return nil, fmt.Errorf(LedgerLockErr, ledger.path, err)
+	}
+
+	lockFile, err := os.OpenFile(ledger.path+LedgerLockSuffix, os.O_CREATE|os.O_RDWR, LedgerFilePermissions)
+	if err != nil {
+		return nil, fmt.Errorf(LedgerLockErr, ledger.path, err)
+	}
+	if err := lockExclusive(lockFile); err != nil {
+		lockFile.Close()
+		return nil, fmt.Errorf(LedgerLockErr, ledger.path, err)
+	}
+
+	return func() {
+		unlockFile(lockFile)
+		lockFile.Close()
+	}, nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package budget
+
+import "os"
+
+func lockExclusive(file *os.File) error {
+	// File locks are only taken on unix, other platforms rely on the ledger mutex of the run
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	// Release the lock of the file
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package budget
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockExclusive(file *os.File) error {
+	// Block until the exclusive lock of the file is taken
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(file *os.File) error {
+	// Release the lock of the file
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,168 @@
+package budget
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/sessions"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func NewTracker(cfg *config.Config) *Tracker {
+	// Create budget tracker, recording usage in the ledger of the data directory
+	return &Tracker{
+		config: cfg,
+		ledger: NewLedger(cfg.GetLedgerPath()),
+		warned: make(map[string]int),
+	}
+}
+
+func (tracker *Tracker) Record(response sessions.ResponseUsage, sessionUsage sessions.Usage) ([]Warning, error) {
+	// Record the usage of a response in the ledger, returning a warning for every budget that newly reached the threshold or its limit
+	recordErr := tracker.ledger.Add(tracker.config.GetLedgerProfile(), time.Now(), response)
+	statuses, statusErr := tracker.GetStatuses(sessionUsage)
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	warnings := []Warning{}
+	for _, status := range statuses {
+		level := status.level(tracker.config.BudgetWarning)
+		key := status.Name()
+		if level <= tracker.warned[key] {
+			continue
+		}
+		tracker.warned[key] = level
+
+		switch level {
+		case BudgetWarningLevel:
+			warnings = append(warnings, Warning{Message: fmt.Sprintf(BudgetWarningText, status.Name(), status.UsedPercent(), status.UsageText())})
+		case BudgetReachedLevel:
+			warnings = append(warnings, Warning{Message: fmt.Sprintf(BudgetReachedText, status.Name(), status.UsageText()), Reached: true})
+		}
+	}
+	return warnings, errors.Join(recordErr, statusErr)
+}
+
+func (tracker *Tracker) Check(sessionUsage sessions.Usage) error {
+	// Return an error when a budget is used up, unless the budgets are overridden
+	if tracker.IsOverridden() {
+		return nil
+	}
+
+	// A ledger that can't be read is reported when usage is recorded, it doesn't refuse prompts
+	statuses, _ := tracker.GetStatuses(sessionUsage)
+	for _, status := range statuses {
+		if status.IsReached() {
+			return fmt.Errorf(BudgetExceededErr, status.Name(), status.UsageText())
+		}
+	}
+	return nil
+}
+
+func (tracker *Tracker) GetStatuses(sessionUsage sessions.Usage) ([]Status, error) {
+	// Return the usage of the session, of today and of the profile against their budgets
+	cfg := tracker.config
+	profile := cfg.GetLedgerProfile()
+	now := time.Now()
+
+	statuses := []Status{
+		{Scope: config.BudgetSessionScope, Usage: sessionUsage, Limit: cfg.Budgets.Session, Currency: cfg.Currency},
+	}
+
+	dayUsage, err := tracker.ledger.GetDay(profile, now)
+	if err != nil {
+		return statuses, err
+	}
+	profileUsage, err := tracker.ledger.GetProfile(profile)
+	if err != nil {
+		return statuses, err
+	}
+
+	return append(statuses,
+		Status{Scope: config.BudgetDayScope, Period: now.Format(LedgerDateLayout), Usage: dayUsage, Limit: cfg.Budgets.Day, Currency: cfg.Currency},
+		Status{Scope: config.BudgetProfileScope, Period: profile, Usage: profileUsage, Limit: cfg.Budgets.Profile, Currency: cfg.Currency},
+	), nil
+}
+
+func (tracker *Tracker) Override() {
+	// Keep accepting prompts over budget for the rest of the run
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.overridden = true
+}
+
+func (tracker *Tracker) IsOverridden() bool {
+	// Return if the budgets are overridden
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	return tracker.overridden
+}
+
+func (status Status) Name() string {
+	// Return the budget scope with its period, e.g. "day 2025-01-31"
+	if status.Period == "" {
+		return status.Scope
+	}
+	return fmt.Sprintf(BudgetPeriodScopeText, status.Scope, status.Period)
+}
+
+func (status Status) IsLimited() bool {
+	// Return if the scope has a token or cost budget
+	return status.Limit.Tokens > 0 || status.Limit.Cost > 0
+}
+
+func (status Status) IsReached() bool {
+	// Return if the usage reached the token or the cost budget
+	tokensReached := status.Limit.Tokens > 0 && status.Usage.TotalTokens >= status.Limit.Tokens
+	costReached := status.Limit.Cost > 0 && status.Usage.Cost >= status.Limit.Cost
+	return tokensReached || costReached
+}
+
+func (status Status) UsedPercent() int {
+	// Return the used percentage of the budget, the larger of the token and cost percentages
+	percent := 0.0
+	if status.Limit.Tokens > 0 {
+		percent = max(percent, float64(status.Usage.TotalTokens)/float64(status.Limit.Tokens)*100)
+	}
+	if status.Limit.Cost > 0 {
+		percent = max(percent, status.Usage.Cost/status.Limit.Cost*100)
+	}
+	return int(percent)
+}
+
+func (status Status) UsageText() string {
+	// Describe the usage against the budget, e.g. "1.2000 of 2.0000 USD, 3000 tokens"
+	parts := []string{}
+	if status.Limit.Cost > 0 {
+		parts = append(parts, fmt.Sprintf(BudgetCostText, status.Usage.Cost, status.Limit.Cost, status.Currency))
+	} else {
+		parts = append(parts, fmt.Sprintf(BudgetUsedCostText, status.Usage.Cost, status.Currency))
+	}
+
+	if status.Limit.Tokens > 0 {
+		parts = append(parts, fmt.Sprintf(BudgetTokensText, status.Usage.TotalTokens, status.Limit.Tokens))
+	} else {
+		parts = append(parts, fmt.Sprintf(BudgetUsedTokensText, status.Usage.TotalTokens))
+	}
+
+	if !status.IsLimited() {
+		parts = append(parts, BudgetNoLimitText)
+	}
+	return strings.Join(parts, BudgetPartsSeparator)
+}
+
+func (status Status) level(warningPercent int) int {
+	// Return the budget level reached by the usage
+	switch {
+	case !status.IsLimited():
+		return BudgetWithinLevel
+	case status.IsReached():
+		return BudgetReachedLevel
+	case status.UsedPercent() >= warningPercent:
+		return BudgetWarningLevel
+	default:
+		return BudgetWithinLevel
+	}
+}
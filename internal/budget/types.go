@@ -0,0 +1,42 @@
+package budget
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/sessions"
+	"sync"
+)
+
+type Ledger struct {
+	// Persistent usage ledger, keeping the usage of every profile by date in a single JSON file
+	mu   sync.Mutex
+	path string
+}
+
+type LedgerFile struct {
+	// Usage ledger file, usage totals by profile and by date
+	Profiles map[string]map[string]sessions.Usage `json:"profiles"`
+}
+
+type Tracker struct {
+	// Budget tracker, checking the session, the day and the profile against their budgets
+	mu         sync.Mutex
+	config     *config.Config
+	ledger     *Ledger
+	overridden bool
+	warned     map[string]int
+}
+
+type Warning struct {
+	// Budget that newly reached the warning threshold or its limit
+	Message string
+	Reached bool
+}
+
+type Status struct {
+	// Usage of a budget scope against its limit, the period is the date or the profile of the ledger scopes
+	Scope    string
+	Period   string
+	Usage    sessions.Usage
+	Limit    config.BudgetConfig
+	Currency string
+}
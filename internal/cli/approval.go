@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"RTGPTGoCLI/internal/cli/ui"
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/internal/functions/handler"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func (cli *CLI) ApproveFunctionCall(ctx context.Context, name string, params functions.FunctionParams) (handler.ApprovalDecision, error) {
+	// Pause the chat and ask the user to approve a function call, one call at a time
+	cli.approvalMu.Lock()
+	defer cli.approvalMu.Unlock()
+
+	answers := make(chan string, 1)
+	cli.setApprovalAnswers(answers)
+	defer cli.setApprovalAnswers(nil)
+
+	cli.stopSpinner()
+	defer cli.startSpinner()
+
+	ui.ClearLine()
+	ui.ShowApprovalRequest(fmt.Sprintf(CLIApprovalRequestText, name), formatArguments(params))
+	ui.ShowPrompt(CLIApprovalPromptText)
+
+	for {
+		select {
+		case <-ctx.Done():
+			ui.EndStreaming()
+			return handler.ApprovalNo, ctx.Err()
+		case answer := <-answers:
+			switch strings.ToLower(answer) {
+			case CLIApprovalYesArg, CLIApprovalYArg:
+				return handler.ApprovalYes, nil
+			case CLIApprovalAlwaysArg, CLIApprovalAArg:
+				return handler.ApprovalAlways, nil
+			case CLIApprovalNoArg, CLIApprovalNArg, "":
+				return handler.ApprovalNo, nil
+			}
+			ui.ShowPrompt(CLIApprovalPromptText)
+		}
+	}
+}
+
+func (cli *CLI) answerApproval(input string) bool {
+	// Route an input line to the pending approval request, returning false when there is none
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+
+	if cli.approvalAnswers == nil {
+		return false
+	}
+
+	// The request stays registered until the approval loop accepts an answer, so invalid answers are asked again
+	select {
+	case cli.approvalAnswers <- input:
+	default:
+	}
+	return true
+}
+
+func (cli *CLI) isApprovalPending() bool {
+	// Return if an approval request waits for an answer
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	return cli.approvalAnswers != nil
+}
+
+func (cli *CLI) setApprovalAnswers(answers chan string) {
+	// Set the channel of the pending approval request
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	cli.approvalAnswers = answers
+}
+
+func formatArguments(params functions.FunctionParams) string {
+	// Pretty print the call arguments
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", params)
+	}
+	return string(data)
+}
@@ -2,32 +2,42 @@ package cli
 
 import (
 	"RTGPTGoCLI/internal/cli/ui"
+	"RTGPTGoCLI/internal/clients"
 	"RTGPTGoCLI/internal/clients/openai"
 	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/internal/sessions"
 	"RTGPTGoCLI/pkg/errorhandler"
 	"RTGPTGoCLI/pkg/logger"
 	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 	"time"
 )
 
 func New(cfg *config.Config, oaiClient openai.OpenAIClientInterface) *CLI {
 	// Create new CLI
+	ui.SetColorEnabled(cfg.UI.Color)
 	return &CLI{
 		config:    cfg,
 		scanner:   bufio.NewScanner(os.Stdin),
 		oaiClient: oaiClient,
-		streamingChannel: make(chan struct{}, cfg.ChannelBuffer),
+		events:    oaiClient.GetMessageChannel(),
+		sessionStore: sessions.NewStore(cfg.GetSessionsDir()),
 		errorHandler: errorhandler.NewErrorHandler(cfg.Debug),
 	}
 }
 
 func (cli *CLI) Run(ctx context.Context, cancel context.CancelFunc) {
 	// Run CLI
+	cli.oaiClient.SetApprover(cli)
 	go cli.handleChatOutput(ctx)
+	go cli.handleInterrupts(ctx, cancel)
 
 	if err := cli.waitUntilReady(ctx); err != nil {
 		appErr := *errorhandler.NewAppError(errorhandler.ErrorLevel, fmt.Sprintf(CLIFailedToWaitUntilReadyErr, err), err)
@@ -41,6 +51,9 @@ func (cli *CLI) Run(ctx context.Context, cancel context.CancelFunc) {
 	for {
 		select {
 		case <-ctx.Done():
+			if session := cli.oaiClient.GetSession(); session.TurnCount() > 0 {
+				ui.ShowInfo(fmt.Sprintf(CLISessionSavedText, session.ID))
+			}
 			ui.ShowGoodbye(CLIGoodbyeText)
 			return
 		default:
@@ -50,6 +63,10 @@ func (cli *CLI) Run(ctx context.Context, cancel context.CancelFunc) {
 				return
 			}
 
+			if cli.answerApproval(inputPrompt) {
+				continue
+			}
+
 			if inputPrompt == "" {
 				continue
 			}
@@ -120,7 +137,29 @@ func (cli *CLI) processInput(ctx context.Context, cancel context.CancelFunc, inp
 		}
 		ui.Show(CLIDebugConfigText, cfgString)
 	case input == CLIPromptFunctionsPrompt || input == CLIPromptFPrompt:
-		ui.ShowFunctions(CLIAvailableFunctionsText, cli.oaiClient.GetAvailableFunctions())
+		ui.ShowFunctions(CLIAvailableFunctionsText, cli.functionsWithPolicies())
+	case isCommand(input, CLIPromptBudget):
+		cli.handleBudgetCommand(commandArgs(input, CLIPromptBudget))
+	case input == CLIPromptUsage:
+		cli.handleUsageCommand()
+	case input == CLIPromptSessions:
+		cli.handleSessionsCommand()
+	case isCommand(input, CLIPromptResume):
+		cli.handleResumeCommand(ctx, commandArgs(input, CLIPromptResume))
+	case isCommand(input, CLIPromptExport):
+		cli.handleExportCommand(commandArgs(input, CLIPromptExport))
+	case isCommand(input, CLIPromptQueue):
+		cli.handleQueueCommand(commandArgs(input, CLIPromptQueue))
+	case isCommand(input, CLIPromptModel):
+		cli.handleModelCommand(ctx, commandArgs(input, CLIPromptModel))
+	case isCommand(input, CLIPromptSystem):
+		cli.handleSystemCommand(ctx, commandText(input, CLIPromptSystem))
+	case isCommand(input, CLIPromptTemperature):
+		cli.handleTemperatureCommand(ctx, commandArgs(input, CLIPromptTemperature))
+	case isCommand(input, CLIPromptMaxTokens):
+		cli.handleMaxTokensCommand(ctx, commandArgs(input, CLIPromptMaxTokens))
+	case isCommand(input, CLIPromptToolChoice):
+		cli.handleToolChoiceCommand(ctx, commandArgs(input, CLIPromptToolChoice))
 	case strings.HasPrefix(input, "/"):
 		ui.ShowError(fmt.Errorf(CLIUnknownCommandText, input))
 	default:
@@ -129,21 +168,55 @@ func (cli *CLI) processInput(ctx context.Context, cancel context.CancelFunc, inp
 }
 
 
+func (cli *CLI) handleInterrupts(ctx context.Context, cancel context.CancelFunc) {
+	// Handle Ctrl+C, cancelling an in-flight response or exiting from an idle prompt
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, os.Interrupt)
+	defer signal.Stop(signalChannel)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signalChannel:
+			if !cli.oaiClient.IsStreaming() {
+				cancel()
+				return
+			}
+
+			if appErr := cli.oaiClient.CancelResponse(ctx); appErr != nil {
+				cli.errorHandler.HandleError(*appErr)
+			}
+		}
+	}
+}
+
 func (cli *CLI) handleChatInput(ctx context.Context, prompt string) {
-	// Handle chat input
+	// Handle chat input, queueing it while a response is still streaming
+	if cli.oaiClient.IsStreaming() {
+		if appErr := cli.oaiClient.SendMessage(ctx, prompt); appErr != nil {
+			cli.errorHandler.HandleError(*appErr)
+			return
+		}
+		ui.ShowInfo(fmt.Sprintf(CLIQueuedText, len(cli.oaiClient.GetQueuedMessages())))
+		return
+	}
+
 	ui.ShowUserMessage(CLIUserPrefixText, prompt)
 
-	go ui.ShowChatProcessing(cli.streamingChannel)
+	cli.startSpinner()
 
 	if appErr := cli.oaiClient.SendMessage(ctx, prompt); appErr != nil {
-		cli.errorHandler.HandleError(*appErr)
+		cli.stopSpinner()
+		ui.ClearLine()
+		ui.ShowError(errors.New(appErr.Message))
 	}
 }
 
 func (cli *CLI) handleChatOutput(ctx context.Context) {
 	// Handle chat output while recovering from panics
 	for {
-		func() {
+		finished := func() (finished bool) {
 			defer func() {
 				if r := recover(); r != nil {
 					logger.Warning(fmt.Sprintf(CLIResponsePanicText, r))
@@ -152,7 +225,11 @@ func (cli *CLI) handleChatOutput(ctx context.Context) {
 			}()
 
 			cli.runChatOutput(ctx) // actual logic
+			return true
 		}()
+		if finished {
+			return
+		}
 	}
 }
 
@@ -164,25 +241,246 @@ func (cli *CLI) runChatOutput(ctx context.Context) {
 			return
 		case appErr := <-cli.oaiClient.GetErrorChannel():
 			cli.errorHandler.HandleError(appErr)
-		case msg := <-cli.oaiClient.GetMessageChannel():
-			if msg.Text == "" && !msg.Done {
-				continue
+		case event, ok := <-cli.events:
+			if !ok {
+				return
 			}
-
-			if isFirstDelta {
-				cli.streamingChannel <- StreamSignal
+			switch event := event.(type) {
+			case clients.TextDeltaEvent:
+				isFirstDelta = cli.showChatDelta(event.Delta, isFirstDelta)
+			case clients.TextDoneEvent:
+				isFirstDelta = cli.showChatDone(isFirstDelta)
+			case clients.ToolCallArgsDeltaEvent:
+				cli.showFunctionCallDelta(event.Call)
+			case clients.ToolCallStartedEvent:
+				cli.showFunctionCallStarted(event.Call)
+			case clients.ToolCallCompletedEvent:
+				cli.showFunctionCallDone(event.Call)
+			case clients.ToolCallFailedEvent:
+				cli.showFunctionCallDone(event.Call)
+			case clients.ResponseDoneEvent:
+				cli.addTurnUsage(event)
+			case clients.TurnDoneEvent:
+				if cli.stopSpinner() || cli.turnUsage.Responses > 0 {
+					ui.ClearLine()
+					cli.showStatusLine()
+					ui.ShowPrompt(CLIPromptText)
+				}
+			case clients.ResponseCancelledEvent:
+				cli.showCancelledOutput(isFirstDelta)
+				isFirstDelta = true
+			case clients.QueuedMessageSentEvent:
 				ui.ClearLine()
-				ui.ShowChatPrefix(CLIChatPrefixText)
-				isFirstDelta = false
-			}
-
-			if !msg.Done {
-				ui.ShowChatDelta(msg.Text)
-			} else {
-				ui.EndStreaming()
+				ui.ShowUserMessage(CLIUserPrefixText, event.Text)
+				cli.startSpinner()
+			case clients.SessionUpdatedEvent:
+				if !event.Requested {
+					continue
+				}
+				ui.ClearLine()
+				ui.ShowInfo(fmt.Sprintf(CLISessionUpdatedText, event.Settings))
+				ui.ShowPrompt(CLIPromptText)
+			case clients.BudgetWarningEvent:
+				cli.showBudgetWarning(event)
+			case clients.SessionUpdateRejectedEvent:
+				ui.ClearLine()
+				ui.ShowError(fmt.Errorf(CLISessionUpdateRejectedErr, event.Reason))
 				ui.ShowPrompt(CLIPromptText)
-				isFirstDelta = true
 			}
 		}
 	}
 }
+
+func (cli *CLI) showChatDelta(delta string, isFirstDelta bool) bool {
+	// Show streamed assistant text, returning if the next delta starts a new message
+	if delta == "" {
+		return isFirstDelta
+	}
+
+	cli.showChatPrefix(isFirstDelta)
+	ui.ShowChatDelta(delta)
+	return false
+}
+
+func (cli *CLI) showChatDone(isFirstDelta bool) bool {
+	// End the streamed assistant text and return to the prompt, the next delta starts a new message
+	cli.showChatPrefix(isFirstDelta)
+	ui.EndStreaming()
+	ui.ShowPrompt(CLIPromptText)
+	return true
+}
+
+func (cli *CLI) showChatPrefix(isFirstDelta bool) {
+	// Replace the processing indicator with the chat prefix before the first delta of a message
+	if !isFirstDelta {
+		return
+	}
+	cli.stopSpinner()
+	ui.ClearLine()
+	ui.ShowChatPrefix(CLIChatPrefixText)
+}
+
+func (cli *CLI) showCancelledOutput(isFirstDelta bool) {
+	// Stop the processing indicator or the streamed line, and return to the prompt
+	if isFirstDelta {
+		cli.stopSpinner()
+		ui.ClearLine()
+	} else {
+		ui.EndStreaming()
+	}
+	ui.ShowInfo(CLIResponseCancelledText)
+	ui.ShowPrompt(CLIPromptText)
+}
+
+func (cli *CLI) showFunctionCallDelta(call clients.FunctionCallEvent) {
+	// Show the live line of a function call whose arguments are being generated, unless an approval question is shown
+	if cli.isApprovalPending() {
+		return
+	}
+	cli.stopSpinner()
+	ui.ClearLine()
+	ui.ShowFunctionCall(fmt.Sprintf(CLIFunctionCallingText, functionName(call), truncateArguments(call.Arguments)), false)
+}
+
+func (cli *CLI) showFunctionCallStarted(call clients.FunctionCallEvent) {
+	// Show the complete call once it runs, keeping the processing indicator while it runs
+	cli.stopSpinner()
+	ui.ClearLine()
+	ui.ShowFunctionCall(fmt.Sprintf(CLIFunctionCallingText, functionName(call), truncateArguments(call.Arguments)), true)
+	cli.resumeAfterFunctionLine()
+}
+
+func (cli *CLI) showFunctionCallDone(call clients.FunctionCallEvent) {
+	// Show the result or error of a call with its duration, then wait for the follow-up response
+	cli.stopSpinner()
+	ui.ClearLine()
+
+	duration := formatDuration(call.DurationMs)
+	if call.Error != "" {
+		ui.ShowFunctionResult(fmt.Sprintf(CLIFunctionFailedText, functionName(call), call.Error, duration), false)
+	} else {
+		ui.ShowFunctionResult(fmt.Sprintf(CLIFunctionReturnedText, functionName(call), resultText(call.Result), duration), true)
+	}
+	cli.resumeAfterFunctionLine()
+}
+
+func (cli *CLI) addTurnUsage(event clients.ResponseDoneEvent) {
+	// Add the usage of a response to the usage of the current turn
+	if event.Usage == nil {
+		return
+	}
+	cli.turnUsage.Add(sessions.ResponseUsage{
+		ResponseID:   event.ResponseID,
+		InputTokens:  event.Usage.InputTokens,
+		OutputTokens: event.Usage.OutputTokens,
+		CachedTokens: event.Usage.CachedTokens,
+		TotalTokens:  event.Usage.TotalTokens,
+		Cost:         event.Usage.Cost,
+		Currency:     event.Usage.Currency,
+	})
+}
+
+func (cli *CLI) showBudgetWarning(event clients.BudgetWarningEvent) {
+	// Show a budget warning above the line in progress
+	ui.ClearLine()
+	if event.Reached {
+		ui.ShowError(errors.New(event.Message))
+	} else {
+		ui.ShowInfo(event.Message)
+	}
+}
+
+func (cli *CLI) showStatusLine() {
+	// Show the tokens and cost of the turn and of the session, then start counting the next turn
+	if cli.turnUsage.Responses == 0 {
+		return
+	}
+	sessionUsage := cli.oaiClient.GetSession().GetUsage()
+	ui.ShowStatus(fmt.Sprintf(CLIStatusLineText, cli.turnUsage.TotalTokens, cli.turnUsage.CostText(), sessionUsage.TotalTokens, sessionUsage.CostText()))
+	cli.turnUsage = sessions.Usage{}
+}
+
+func (cli *CLI) resumeAfterFunctionLine() {
+	// Show the processing indicator again, or the question of an approval printed over by the function line
+	if cli.isApprovalPending() {
+		ui.ShowPrompt(CLIApprovalPromptText)
+		return
+	}
+	cli.startSpinner()
+}
+
+func (cli *CLI) startSpinner() {
+	// Show the processing indicator, unless it is already shown
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.spinnerStop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	cli.spinnerStop = stop
+	cli.spinnerDone = done
+	go func() {
+		defer close(done)
+		ui.ShowChatProcessing(stop)
+	}()
+}
+
+func (cli *CLI) stopSpinner() bool {
+	// Stop the processing indicator and wait until it is gone, returning if it was shown
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+	if cli.spinnerStop == nil {
+		return false
+	}
+
+	close(cli.spinnerStop)
+	<-cli.spinnerDone
+	cli.spinnerStop = nil
+	cli.spinnerDone = nil
+	return true
+}
+
+func functionName(function clients.FunctionCallEvent) string {
+	// Return the function name, a placeholder until the server sent it
+	if function.Name == "" {
+		return CLIUnknownFunctionNameText
+	}
+	return function.Name
+}
+
+func formatDuration(durationMs int64) string {
+	// Format a call duration, in milliseconds below one second
+	duration := time.Duration(durationMs) * time.Millisecond
+	if duration < time.Second {
+		return fmt.Sprintf(CLIMillisecondsText, durationMs)
+	}
+	return duration.Round(CLIDurationPrecision).String()
+}
+
+func truncateArguments(arguments string) string {
+	// Shorten the arguments to fit on a single line
+	arguments = strings.Join(strings.Fields(arguments), " ")
+	if runes := []rune(arguments); len(runes) > CLIFunctionArgumentsMaxLength {
+		return string(runes[:CLIFunctionArgumentsMaxLength]) + CLIEllipsisText
+	}
+	return arguments
+}
+
+func resultText(result interface{}) string {
+	// Return the summary of a function result, or its data as compact JSON
+	functionResult, ok := result.(*functions.FunctionResult)
+	if !ok || functionResult == nil {
+		return truncateArguments(fmt.Sprintf("%v", result))
+	}
+	if functionResult.Summary != "" {
+		return functionResult.Summary
+	}
+
+	data, err := json.Marshal(functionResult.Data)
+	if err != nil {
+		return truncateArguments(fmt.Sprintf("%v", functionResult.Data))
+	}
+	return truncateArguments(string(data))
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"RTGPTGoCLI/internal/cli/ui"
+	"RTGPTGoCLI/internal/clients/openai"
+	"RTGPTGoCLI/internal/export"
+	"RTGPTGoCLI/internal/sessions"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func isCommand(input string, command string) bool {
+	// Return if input is the command, with or without arguments
+	return input == command || strings.HasPrefix(input, command+" ")
+}
+
+func commandArgs(input string, command string) []string {
+	// Return the whitespace separated arguments of a command
+	return strings.Fields(strings.TrimPrefix(input, command))
+}
+
+func commandText(input string, command string) string {
+	// Return the raw text following a command
+	return strings.TrimSpace(strings.TrimPrefix(input, command))
+}
+
+func (cli *CLI) handleQueueCommand(args []string) {
+	// List, drop or clear prompts queued while a response is streaming
+	switch {
+	case len(args) == 0:
+		ui.ShowQueue(CLIQueuedMessagesText, cli.oaiClient.GetQueuedMessages())
+	case args[0] == CLIQueueClearArg && len(args) == 1:
+		ui.ShowInfo(fmt.Sprintf(CLIQueueClearedText, cli.oaiClient.ClearQueue()))
+	case args[0] == CLIQueueDropArg && len(args) == 2:
+		position, err := strconv.Atoi(args[1])
+		if err != nil || !cli.oaiClient.DropQueuedMessage(position-1) {
+			ui.ShowError(fmt.Errorf(CLIQueueInvalidPositionErr, args[1]))
+			return
+		}
+		ui.ShowInfo(fmt.Sprintf(CLIQueueDroppedText, position))
+	default:
+		ui.ShowError(fmt.Errorf(CLIQueueUsageErr))
+	}
+}
+
+func (cli *CLI) handleSessionsCommand() {
+	// List saved chat sessions with their title, date and turn count
+	summaries, err := cli.sessionStore.List()
+	if err != nil {
+		ui.ShowError(err)
+		return
+	}
+
+	lines := make([]string, len(summaries))
+	for i, summary := range summaries {
+		lines[i] = fmt.Sprintf(CLISessionLineText, summary.ID, summary.UpdatedAt.Format(CLISessionDateLayout), summary.Turns, summary.Title)
+	}
+	ui.ShowList(CLISavedSessionsText, lines)
+}
+
+func (cli *CLI) handleUsageCommand() {
+	// Show the token usage and cost of the current session and of its last response
+	session := cli.oaiClient.GetSession()
+	usage := session.GetUsage()
+	lines := []string{
+		fmt.Sprintf(CLIUsageResponsesText, usage.Responses),
+		fmt.Sprintf(CLIUsageTokensText, usage.TokensText()),
+		fmt.Sprintf(CLIUsageCostText, usage.CostText()),
+	}
+
+	if responses := session.GetResponses(); len(responses) > 0 {
+		last := responses[len(responses)-1]
+		lastUsage := sessions.Usage{}
+		lastUsage.Add(last)
+		costText := CLIUsageUnpricedCostText
+		if last.Cost != nil {
+			costText = lastUsage.CostText()
+		}
+		lines = append(lines, fmt.Sprintf(CLIUsageLastResponseText, last.Model, lastUsage.TokensText(), costText))
+	}
+	ui.ShowList(fmt.Sprintf(CLIUsageTitleText, session.ID), lines)
+}
+
+func (cli *CLI) handleBudgetCommand(args []string) {
+	// Show the budgets with their usage, or override them for the rest of the run
+	switch {
+	case len(args) == 0:
+		statuses, err := cli.oaiClient.GetBudgetStatuses()
+		lines := make([]string, len(statuses))
+		for i, status := range statuses {
+			lines[i] = fmt.Sprintf(CLIBudgetStatusText, status.Name(), status.UsageText())
+		}
+		ui.ShowList(CLIBudgetsText, lines)
+		if err != nil {
+			ui.ShowError(err)
+		}
+		if cli.oaiClient.IsBudgetOverridden() {
+			ui.ShowInfo(CLIBudgetOverriddenStatusText)
+		}
+	case len(args) == 1 && args[0] == CLIBudgetOverrideArg:
+		cli.oaiClient.OverrideBudget()
+		ui.ShowInfo(CLIBudgetOverriddenText)
+	default:
+		ui.ShowError(fmt.Errorf(CLIBudgetUsageErr))
+	}
+}
+
+func (cli *CLI) handleResumeCommand(ctx context.Context, args []string) {
+	// Resume a saved chat session by its ID
+	if len(args) != 1 {
+		ui.ShowError(fmt.Errorf(CLIResumeUsageErr))
+		return
+	}
+
+	if appErr := cli.oaiClient.ResumeSession(ctx, args[0]); appErr != nil {
+		ui.ShowError(errors.New(appErr.Message))
+		return
+	}
+
+	session := cli.oaiClient.GetSession()
+	ui.ShowInfo(fmt.Sprintf(CLISessionResumedText, session.ID, session.TurnCount()))
+}
+
+func (cli *CLI) handleExportCommand(args []string) {
+	// Export the current session transcript
+	if len(args) != 2 {
+		ui.ShowError(fmt.Errorf(CLIExportUsageErr))
+		return
+	}
+
+	if err := export.WriteFile(cli.oaiClient.GetSession(), args[0], args[1]); err != nil {
+		ui.ShowError(err)
+		return
+	}
+	ui.ShowInfo(fmt.Sprintf(CLIExportedText, args[1]))
+}
+
+func (cli *CLI) handleModelCommand(ctx context.Context, args []string) {
+	// Show the model, or switch to another one keeping the conversation
+	if len(args) == 0 {
+		ui.ShowInfo(fmt.Sprintf(CLICurrentSettingText, CLIModelSettingText, cli.oaiClient.GetSessionSettings().Model))
+		return
+	}
+	if len(args) != 1 {
+		ui.ShowError(fmt.Errorf(CLIModelUsageErr))
+		return
+	}
+
+	if appErr := cli.oaiClient.SetModel(ctx, args[0]); appErr != nil {
+		ui.ShowError(errors.New(appErr.Message))
+		return
+	}
+	ui.ShowInfo(fmt.Sprintf(CLISwitchingModelText, args[0]))
+}
+
+func (cli *CLI) handleSystemCommand(ctx context.Context, instructions string) {
+	// Show or replace the session instructions
+	if instructions == "" {
+		ui.ShowInfo(fmt.Sprintf(CLICurrentSettingText, CLIInstructionsSettingText, cli.oaiClient.GetSessionSettings().Instructions))
+		return
+	}
+
+	cli.updateSession(ctx, openai.OAISessionUpdate{Instructions: &instructions})
+}
+
+func (cli *CLI) handleTemperatureCommand(ctx context.Context, args []string) {
+	// Change the sampling temperature
+	if len(args) != 1 {
+		ui.ShowError(fmt.Errorf(CLITemperatureUsageErr))
+		return
+	}
+
+	temperature, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || temperature < 0 {
+		ui.ShowError(fmt.Errorf(CLITemperatureUsageErr))
+		return
+	}
+
+	cli.updateSession(ctx, openai.OAISessionUpdate{Temperature: &temperature})
+}
+
+func (cli *CLI) handleMaxTokensCommand(ctx context.Context, args []string) {
+	// Change the max output tokens, a positive number or inf
+	if len(args) != 1 {
+		ui.ShowError(fmt.Errorf(CLIMaxTokensUsageErr))
+		return
+	}
+
+	var maxOutputTokens interface{} = args[0]
+	if args[0] != CLIMaxTokensInfArg {
+		tokens, err := strconv.Atoi(args[0])
+		if err != nil || tokens <= 0 {
+			ui.ShowError(fmt.Errorf(CLIMaxTokensUsageErr))
+			return
+		}
+		maxOutputTokens = tokens
+	}
+
+	cli.updateSession(ctx, openai.OAISessionUpdate{MaxOutputTokens: maxOutputTokens})
+}
+
+func (cli *CLI) handleToolChoiceCommand(ctx context.Context, args []string) {
+	// Change the tool choice to auto, none, required or a specific function
+	if len(args) != 1 {
+		ui.ShowError(fmt.Errorf(CLIToolChoiceUsageErr))
+		return
+	}
+
+	var toolChoice interface{} = args[0]
+	switch args[0] {
+	case CLIToolChoiceAutoArg, CLIToolChoiceNoneArg, CLIToolChoiceRequiredArg:
+	default:
+		if !slices.Contains(cli.oaiClient.GetAvailableFunctions(), args[0]) {
+			ui.ShowError(fmt.Errorf(CLIUnknownFunctionErr, args[0]))
+			return
+		}
+		toolChoice = openai.OAIToolChoiceFunction{Type: openai.OAIToolChoiceFunctionType, Name: args[0]}
+	}
+
+	cli.updateSession(ctx, openai.OAISessionUpdate{ToolChoice: toolChoice})
+}
+
+func (cli *CLI) updateSession(ctx context.Context, update openai.OAISessionUpdate) {
+	// Send session update, the change is confirmed once the server sends session.updated
+	if appErr := cli.oaiClient.UpdateSession(ctx, update); appErr != nil {
+		ui.ShowError(errors.New(appErr.Message))
+		return
+	}
+	ui.ShowInfo(CLISessionUpdateSentText)
+}
+
+func (cli *CLI) functionsWithPolicies() []string {
+	// List the available functions with their approval policy
+	names := cli.oaiClient.GetAvailableFunctions()
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf(CLIFunctionPolicyText, name, cli.oaiClient.GetToolPolicy(name))
+	}
+	return lines
+}
@@ -1,10 +1,26 @@
 package cli
 
+import "time"
+
 const (
 	// Errors
 	CLIFailedToWaitUntilReadyErr = "failed to wait until ready: %v"
 	CLIFailedToGetInputErr = "failed to get input: %v"
 	CLIFailedInputScannerErr = "input scanner error: %v"
+	CLIQueueInvalidPositionErr = "invalid queue position: %s"
+	CLIQueueUsageErr = "usage: /queue [drop <n> | clear]"
+	CLIFailedToReadStdinErr = "failed to read prompt from stdin: %v"
+	CLIOneShotTimeoutErr = "no response received within %d seconds"
+	CLIOneShotConnectionClosedErr = "connection closed before the response was done"
+	CLIResumeUsageErr = "usage: /resume <session-id>"
+	CLIExportUsageErr = "usage: /export <markdown|html|json> <path>"
+	CLIModelUsageErr = "usage: /model [name]"
+	CLITemperatureUsageErr = "usage: /temperature <non-negative number>"
+	CLIMaxTokensUsageErr = "usage: /max-tokens <positive number|inf>"
+	CLIBudgetUsageErr = "usage: /budget [override]"
+	CLIToolChoiceUsageErr = "usage: /tool-choice <auto|none|required|function-name>"
+	CLIUnknownFunctionErr = "unknown function: %s"
+	CLISessionUpdateRejectedErr = "session update rejected: %s"
 )
 
 const (
@@ -18,6 +34,34 @@ const (
 	CLIPromptHPrompt     string = "/h"
 	CLIPromptFunctionsPrompt string = "/functions"
 	CLIPromptFPrompt     string = "/f"
+	CLIPromptQueue       string = "/queue"
+	CLIPromptSessions    string = "/sessions"
+	CLIPromptResume      string = "/resume"
+	CLIPromptExport      string = "/export"
+	CLIPromptModel       string = "/model"
+	CLIPromptSystem      string = "/system"
+	CLIPromptTemperature string = "/temperature"
+	CLIPromptMaxTokens   string = "/max-tokens"
+	CLIPromptToolChoice  string = "/tool-choice"
+	CLIPromptUsage       string = "/usage"
+	CLIPromptBudget      string = "/budget"
+)
+
+const (
+	// Cli command arguments
+	CLIQueueDropArg  = "drop"
+	CLIQueueClearArg = "clear"
+	CLIMaxTokensInfArg = "inf"
+	CLIToolChoiceAutoArg = "auto"
+	CLIToolChoiceNoneArg = "none"
+	CLIToolChoiceRequiredArg = "required"
+	CLIApprovalYesArg = "yes"
+	CLIApprovalYArg = "y"
+	CLIApprovalNoArg = "no"
+	CLIApprovalNArg = "n"
+	CLIApprovalAlwaysArg = "always"
+	CLIApprovalAArg = "a"
+	CLIBudgetOverrideArg = "override"
 )
 
 const (
@@ -32,8 +76,20 @@ const (
 	/help, /h		Show this help message
 	/debug			Toggle debug mode
 	/functions, /f		Show available functions
+	/queue [drop <n> | clear]	List, drop or clear prompts queued while streaming
+	/sessions		List saved chat sessions
+	/resume <id>		Resume a saved chat session
+	/export <format> <path>	Export this session as markdown, html or json
+	/model [name]		Show or switch the model, keeping the conversation
+	/system [text]		Show or replace the session instructions
+	/temperature <value>	Set the sampling temperature
+	/max-tokens <n|inf>	Set the max output tokens of a response
+	/tool-choice <choice>	Set the tool choice: auto, none, required or a function name
+	/usage			Show the token usage and cost of this session
+	/budget [override]	Show the budgets, or keep sending prompts over budget
 	clear			Clear the screen
 	exit, quit, /q		Exit the application
+	Ctrl+C			Cancel the current response, or exit from an idle prompt
 `
 	
 	CLIWelcomeText = "Chat with Me!"
@@ -46,7 +102,43 @@ const (
 	CLIChatPrefixText = "Chat: "
 	CLIAvailableFunctionsText = "Available functions:"
 	CLIDebugConfigText = "Debug config: %v"
+	CLIResponseCancelledText = "Response cancelled."
+	CLIQueuedText = "queued (%d)"
+	CLIQueuedMessagesText = "Queued prompts:"
+	CLIQueueDroppedText = "Dropped queued prompt %d."
+	CLIQueueClearedText = "Dropped %d queued prompts."
+	CLISavedSessionsText = "Saved sessions:"
+	CLISessionLineText = "%s  %s  %d turns  %s"
+	CLISessionDateLayout = "2006-01-02 15:04"
+	CLISessionResumedText = "Resumed session %s (%d turns)."
+	CLISessionSavedText = "Session saved, resume it with: --resume %s"
+	CLIExportedText = "Transcript exported to %s."
+	CLICurrentSettingText = "Current %s: %v"
+	CLIModelSettingText = "model"
+	CLIInstructionsSettingText = "instructions"
+	CLISwitchingModelText = "Switching to model %s, waiting for the server to confirm..."
+	CLISessionUpdateSentText = "Session update sent, waiting for the server to confirm..."
+	CLISessionUpdatedText = "Session updated: %s"
+	CLIApprovalRequestText = "The assistant wants to call %s with:"
+	CLIApprovalPromptText = "Allow? [y]es / [N]o / [a]lways for this session: "
+	CLIFunctionPolicyText = "%s (%s)"
+	CLIUsageTitleText = "Usage of session %s:"
+	CLIUsageResponsesText = "Responses: %d"
+	CLIUsageTokensText = "Tokens: %s"
+	CLIUsageCostText = "Cost: %s"
+	CLIUsageLastResponseText = "Last response (%s): %s, %s"
+	CLIUsageUnpricedCostText = "no price"
+	CLIBudgetsText = "Budgets:"
+	CLIBudgetStatusText = "%s: %s"
+	CLIBudgetOverriddenText = "Budgets overridden, prompts are sent over budget until you exit."
+	CLIBudgetOverriddenStatusText = "Budgets are overridden for this run."
+	CLIStatusLineText = "turn %d tokens, %s · session %d tokens, %s"
+	CLIFunctionCallingText = "Calling %s(%s)"
+	CLIFunctionReturnedText = "  %s returned %s in %s"
+	CLIFunctionFailedText = "  %s failed: %s (%s)"
+	CLIUnknownFunctionNameText = "function"
+	CLIEllipsisText = "…"
+	CLIFunctionArgumentsMaxLength = 60
+	CLIMillisecondsText = "%dms"
+	CLIDurationPrecision = 100 * time.Millisecond
 )
-
-// Signal token for streaming
-var StreamSignal = struct{}{}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"RTGPTGoCLI/internal/cli/output"
+	"RTGPTGoCLI/internal/clients"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+func GetOneShotPrompt(flagPrompt string) (string, error) {
+	// Build the one-shot prompt from the -p flag and piped stdin, empty when interactive
+	pipedPrompt, err := readPipedInput()
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{}
+	for _, part := range []string{strings.TrimSpace(flagPrompt), pipedPrompt} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+func readPipedInput() (string, error) {
+	// Read stdin only when it is a pipe or a redirected file, a terminal or an open socket would block
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return "", fmt.Errorf(CLIFailedToReadStdinErr, err)
+	}
+
+	if stat.Mode()&os.ModeNamedPipe == 0 && !stat.Mode().IsRegular() {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf(CLIFailedToReadStdinErr, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (cli *CLI) RunOneShot(ctx context.Context, prompt string) error {
+	// Send a single prompt, write the response in the configured output format and return once the turn is done
+	writer, err := output.NewWriter(cli.config.Output, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	runErr := cli.runOneShot(ctx, prompt, writer)
+	if runErr != nil {
+		writer.WriteError(runErr)
+	}
+
+	if err := writer.Close(); err != nil && runErr == nil {
+		return err
+	}
+	return runErr
+}
+
+func (cli *CLI) runOneShot(ctx context.Context, prompt string, writer output.Writer) error {
+	// Run the one-shot turn, forwarding every client event to the output writer
+	if err := cli.waitUntilReady(ctx); err != nil {
+		return fmt.Errorf(CLIFailedToWaitUntilReadyErr, err)
+	}
+
+	if appErr := cli.oaiClient.SendMessage(ctx, prompt); appErr != nil {
+		return appErrorToError(appErr)
+	}
+
+	// The inactivity timer is paused while tools run, they are bounded by their own timeouts
+	inactivityTimeout := time.Duration(cli.config.Timeout) * time.Second
+	inactivityTimer := time.NewTimer(inactivityTimeout)
+	defer inactivityTimer.Stop()
+	runningTools := map[string]bool{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-inactivityTimer.C:
+			return fmt.Errorf(CLIOneShotTimeoutErr, cli.config.Timeout)
+		case appErr, ok := <-cli.oaiClient.GetErrorChannel():
+			if !ok {
+				return errors.New(CLIOneShotConnectionClosedErr)
+			}
+			if appErr.Level == errorhandler.ErrorLevel {
+				return appErrorToError(&appErr)
+			}
+			cli.errorHandler.HandleError(appErr)
+		case event, ok := <-cli.events:
+			if !ok {
+				return errors.New(CLIOneShotConnectionClosedErr)
+			}
+			switch typed := event.(type) {
+			case clients.ToolCallStartedEvent:
+				runningTools[typed.Call.CallID] = true
+			case clients.ToolCallCompletedEvent:
+				delete(runningTools, typed.Call.CallID)
+			case clients.ToolCallFailedEvent:
+				delete(runningTools, typed.Call.CallID)
+			}
+			if len(runningTools) > 0 {
+				inactivityTimer.Stop()
+			} else {
+				inactivityTimer.Reset(inactivityTimeout)
+			}
+			writer.WriteEvent(event)
+
+			if _, turnDone := event.(clients.TurnDoneEvent); turnDone {
+				return cli.drainOneShotErrors()
+			}
+		}
+	}
+}
+
+func (cli *CLI) drainOneShotErrors() error {
+	// Check errors reported alongside the end of the turn, e.g. a failed response.done
+	for {
+		select {
+		case appErr, ok := <-cli.oaiClient.GetErrorChannel():
+			if !ok {
+				return nil
+			}
+			if appErr.Level == errorhandler.ErrorLevel {
+				return appErrorToError(&appErr)
+			}
+			cli.errorHandler.HandleError(appErr)
+		default:
+			return nil
+		}
+	}
+}
+
+func appErrorToError(appErr *errorhandler.AppError) error {
+	// Convert app error to a plain error for the process exit status
+	return errors.New(appErr.Message)
+}
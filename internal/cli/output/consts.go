@@ -0,0 +1,20 @@
+package output
+
+const (
+	// Record types
+	OutputDeltaRecordType        = "delta"
+	OutputTextRecordType         = "text"
+	OutputFunctionCallDeltaRecordType = "function_call_delta"
+	OutputFunctionCallStartedRecordType = "function_call_started"
+	OutputFunctionCallRecordType = "function_call"
+	OutputResponseRecordType     = "response"
+	OutputRateLimitsRecordType   = "rate_limits"
+	OutputBudgetWarningRecordType = "budget_warning"
+	OutputDoneRecordType         = "done"
+	OutputErrorRecordType        = "error"
+)
+
+const (
+	// Errors
+	OutputUnknownFormatErr = "unknown output format: %s"
+)
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"RTGPTGoCLI/internal/clients"
+	"RTGPTGoCLI/internal/config"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func NewWriter(format string, out io.Writer) (Writer, error) {
+	// Create output writer for the given format
+	switch format {
+	case config.OutputFormatText:
+		return &TextWriter{out: out}, nil
+	case config.OutputFormatNDJSON:
+		return &NDJSONWriter{encoder: json.NewEncoder(out)}, nil
+	case config.OutputFormatJSON:
+		return &JSONWriter{out: out, document: newDocument()}, nil
+	default:
+		return nil, fmt.Errorf(OutputUnknownFormatErr, format)
+	}
+}
+
+func (tw *TextWriter) WriteEvent(event clients.Event) {
+	// Print assistant text deltas, ending each text with a new line
+	switch event := event.(type) {
+	case clients.TextDeltaEvent:
+		fmt.Fprint(tw.out, event.Delta)
+	case clients.TextDoneEvent:
+		fmt.Fprintln(tw.out)
+	}
+}
+
+func (tw *TextWriter) WriteError(err error) {
+	// Errors are reported on stderr by the caller in text mode
+}
+
+func (tw *TextWriter) Close() error {
+	// Nothing to flush in text mode
+	return nil
+}
+
+func (nw *NDJSONWriter) WriteEvent(event clients.Event) {
+	// Encode event as a single record line
+	if record, ok := toRecord(event); ok {
+		nw.encoder.Encode(record)
+	}
+}
+
+func (nw *NDJSONWriter) WriteError(err error) {
+	// Encode error as a single record line
+	nw.encoder.Encode(Record{Type: OutputErrorRecordType, Error: err.Error()})
+}
+
+func (nw *NDJSONWriter) Close() error {
+	// Records are written as they arrive
+	return nil
+}
+
+func (jw *JSONWriter) WriteEvent(event clients.Event) {
+	// Collect event into the output document
+	document := &jw.document
+	switch event := event.(type) {
+	case clients.TextDeltaEvent:
+		document.Deltas = append(document.Deltas, event.Delta)
+	case clients.TextDoneEvent:
+		document.Text = joinText(document.Text, event.Text)
+	case clients.ToolCallCompletedEvent:
+		document.FunctionCalls = append(document.FunctionCalls, event.Call)
+	case clients.ToolCallFailedEvent:
+		document.FunctionCalls = append(document.FunctionCalls, event.Call)
+	case clients.ResponseDoneEvent:
+		document.ResponseIDs = append(document.ResponseIDs, event.ResponseID)
+		if event.Usage != nil {
+			document.Usage.InputTokens += event.Usage.InputTokens
+			document.Usage.OutputTokens += event.Usage.OutputTokens
+			document.Usage.CachedTokens += event.Usage.CachedTokens
+			document.Usage.TotalTokens += event.Usage.TotalTokens
+			document.Usage.Currency = event.Usage.Currency
+			if event.Usage.Cost != nil {
+				cost := *event.Usage.Cost
+				if document.Usage.Cost != nil {
+					cost += *document.Usage.Cost
+				}
+				document.Usage.Cost = &cost
+			}
+		}
+	}
+}
+
+func (jw *JSONWriter) WriteError(err error) {
+	// Collect error into the output document
+	jw.document.Errors = append(jw.document.Errors, err.Error())
+}
+
+func (jw *JSONWriter) Close() error {
+	// Write the collected document
+	jsonBytes, err := json.MarshalIndent(jw.document, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(jw.out, string(jsonBytes))
+	return err
+}
+
+func toRecord(event clients.Event) (Record, bool) {
+	// Convert client event to an output record, skipping events with no output meaning
+	switch event := event.(type) {
+	case clients.TextDeltaEvent:
+		return Record{Type: OutputDeltaRecordType, ResponseID: event.ResponseID, Text: event.Delta}, true
+	case clients.TextDoneEvent:
+		return Record{Type: OutputTextRecordType, ResponseID: event.ResponseID, Text: event.Text}, true
+	case clients.ToolCallArgsDeltaEvent:
+		return Record{Type: OutputFunctionCallDeltaRecordType, ResponseID: event.ResponseID, Text: event.Delta, Function: &event.Call}, true
+	case clients.ToolCallStartedEvent:
+		return Record{Type: OutputFunctionCallStartedRecordType, ResponseID: event.ResponseID, Function: &event.Call}, true
+	case clients.ToolCallCompletedEvent:
+		return Record{Type: OutputFunctionCallRecordType, ResponseID: event.ResponseID, Function: &event.Call}, true
+	case clients.ToolCallFailedEvent:
+		return Record{Type: OutputFunctionCallRecordType, ResponseID: event.ResponseID, Function: &event.Call}, true
+	case clients.ResponseDoneEvent:
+		return Record{Type: OutputResponseRecordType, ResponseID: event.ResponseID, Usage: event.Usage}, true
+	case clients.RateLimitsEvent:
+		return Record{Type: OutputRateLimitsRecordType, RateLimits: event.Limits}, true
+	case clients.BudgetWarningEvent:
+		return Record{Type: OutputBudgetWarningRecordType, Text: event.Message}, true
+	case clients.TurnDoneEvent:
+		return Record{Type: OutputDoneRecordType, ResponseID: event.ResponseID}, true
+	default:
+		return Record{}, false
+	}
+}
+
+func newDocument() Document {
+	// Create empty document, keeping lists as empty arrays in the output
+	return Document{
+		ResponseIDs:   []string{},
+		Deltas:        []string{},
+		FunctionCalls: []clients.FunctionCallEvent{},
+		Errors:        []string{},
+	}
+}
+
+func joinText(existing string, text string) string {
+	// Join texts of consecutive responses in the same turn
+	if existing == "" {
+		return text
+	}
+	return strings.Join([]string{existing, text}, "\n")
+}
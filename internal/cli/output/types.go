@@ -0,0 +1,51 @@
+package output
+
+import (
+	"RTGPTGoCLI/internal/clients"
+	"encoding/json"
+	"io"
+)
+
+type Writer interface {
+	// Output writer interface, rendering client events in a given format
+	WriteEvent(event clients.Event)
+	WriteError(err error)
+	Close() error
+}
+
+type TextWriter struct {
+	// Plain text writer, printing only the assistant text
+	out io.Writer
+}
+
+type NDJSONWriter struct {
+	// Newline delimited JSON writer, one record per event
+	encoder *json.Encoder
+}
+
+type JSONWriter struct {
+	// JSON writer, collecting every event into a single document written on close
+	out      io.Writer
+	document Document
+}
+
+type Record struct {
+	// Structured output record
+	Type       string                     `json:"type"`
+	ResponseID string                     `json:"response_id,omitempty"`
+	Text       string                     `json:"text,omitempty"`
+	Function   *clients.FunctionCallEvent `json:"function,omitempty"`
+	Usage      *clients.UsageEvent        `json:"usage,omitempty"`
+	RateLimits []clients.RateLimit        `json:"rate_limits,omitempty"`
+	Error      string                     `json:"error,omitempty"`
+}
+
+type Document struct {
+	// Structured output document of a whole run
+	ResponseIDs   []string                    `json:"response_ids"`
+	Text          string                      `json:"text"`
+	Deltas        []string                    `json:"deltas"`
+	FunctionCalls []clients.FunctionCallEvent `json:"function_calls"`
+	Usage         clients.UsageEvent          `json:"usage"`
+	Errors        []string                    `json:"errors"`
+}
@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"RTGPTGoCLI/internal/clients"
 	"RTGPTGoCLI/internal/clients/openai"
 	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/sessions"
 	"RTGPTGoCLI/pkg/errorhandler"
 	"bufio"
+	"sync"
 )
 
 type CLI struct {
@@ -12,6 +15,14 @@ type CLI struct {
 	config    *config.Config
 	scanner   *bufio.Scanner
 	oaiClient openai.OpenAIClientInterface
-	streamingChannel chan struct{}
+	events    <-chan clients.Event
+	sessionStore *sessions.Store
 	errorHandler *errorhandler.ErrorHandler
+
+	mu              sync.Mutex
+	approvalMu      sync.Mutex
+	approvalAnswers chan string
+	spinnerStop     chan struct{}
+	spinnerDone     chan struct{}
+	turnUsage       sessions.Usage
 }
@@ -10,6 +10,7 @@ const (
 	UIRedColor = "\033[31m"
 	UICyanColor = "\033[36m"
 	UIYellowColor = "\033[33m"
+	UIGrayColor = "\033[90m"
 )
 
 const (
@@ -27,3 +28,6 @@ const (
 
 // Dots for streaming
 var UIProcessingDots = [...]string{".  ", ".. ", "..."}
+
+// Coloured output toggle
+var colorEnabled = true
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/pkg/redact"
 	"fmt"
 	"strings"
 	"time"
@@ -15,9 +16,9 @@ func stringRepeat(str string, count int) string {
 func ShowWelcome(welcomeText string, additionalText string) {
 	// show welcome message
 	line := "╔" + stringRepeat("═", len(welcomeText) + 4) + "╗"
-	fmt.Println(UICyanColor + line + UIResetColor)
-	fmt.Println(UICyanColor + "║" + UIYellowColor + "  " + welcomeText + "  " + UICyanColor + "║" + UIResetColor)
-	fmt.Println(UICyanColor + "╚" + stringRepeat("═", len(welcomeText) + 4) + "╝" + UIResetColor)
+	fmt.Println(color(UICyanColor) + line + color(UIResetColor))
+	fmt.Println(color(UICyanColor) + "║" + color(UIYellowColor) + "  " + welcomeText + "  " + color(UICyanColor) + "║" + color(UIResetColor))
+	fmt.Println(color(UICyanColor) + "╚" + stringRepeat("═", len(welcomeText) + 4) + "╝" + color(UIResetColor))
 	fmt.Println()
 	fmt.Println(additionalText)
 	fmt.Println()
@@ -31,7 +32,7 @@ func ShowGoodbye(goodbyeText string) {
 
 func ShowPrompt(promptText string) {
 	// show input prompt
-	fmt.Print(UIBlueColor + promptText + UIResetColor)
+	fmt.Print(color(UIBlueColor) + promptText + color(UIResetColor))
 }
 
 func Clear() {
@@ -41,7 +42,17 @@ func Clear() {
 
 func ShowError(err error) {
 	// show error message
-	fmt.Println(UIRedColor + UIErrorPrefix + UIResetColor + err.Error())
+	fmt.Println(color(UIRedColor) + UIErrorPrefix + color(UIResetColor) + redact.String(err.Error()))
+}
+
+func ShowInfo(text string) {
+	// show info message
+	fmt.Println(color(UIYellowColor) + text + color(UIResetColor))
+}
+
+func ShowStatus(text string) {
+	// show a dimmed status line
+	fmt.Println(color(UIGrayColor) + text + color(UIResetColor))
 }
 
 func Show(prefix string, text string) {
@@ -66,13 +77,13 @@ func EndStreaming() {
 
 func ShowUserMessage(prefix string, message string) {
 	// show user message with a user prefix
-	fmt.Printf(UIBlueColor + prefix + UIResetColor + "%s\n", message)
+	fmt.Printf(color(UIBlueColor) + prefix + color(UIResetColor) + "%s\n", message)
 }
 
 func ShowChatPrefix(prefix string) {
 	// show chatbot message prefix
 	ClearLine()
-	fmt.Print(UIGreenColor + prefix + UIResetColor)
+	fmt.Print(color(UIGreenColor) + prefix + color(UIResetColor))
 }
 
 func ShowChatDelta(delta string) {
@@ -85,21 +96,44 @@ func ClearLine() {
 	fmt.Print(UIClearLineCommand)
 }
 
-func ShowChatProcessing(streamingChannel <-chan struct{}) {
-	// show chatbot message processing with changing dots
-	i := 0
-	for {
+func ShowChatProcessing(stopChannel <-chan struct{}) {
+	// show chatbot message processing with changing dots, until the stop channel is closed
+	ticker := time.NewTicker(UIProcessSleepTime)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		fmt.Printf(UIProcessingText, color(UIGreenColor), UIProcessingDots[i%len(UIProcessingDots)], color(UIResetColor))
 		select {
-		case <-streamingChannel:
+		case <-stopChannel:
 			return
-		default:
-			fmt.Printf(UIProcessingText, UIGreenColor, UIProcessingDots[i%len(UIProcessingDots)], UIResetColor)
-			i++
-			time.Sleep(UIProcessSleepTime)
+		case <-ticker.C:
 		}
 	}
 }
 
+func ShowFunctionCall(text string, done bool) {
+	// show a function call line, kept open while its arguments are still streaming
+	fmt.Print(color(UICyanColor) + text + color(UIResetColor))
+	if done {
+		fmt.Println()
+	}
+}
+
+func ShowFunctionResult(text string, succeeded bool) {
+	// show the result or error of a function call
+	textColor := UIGreenColor
+	if !succeeded {
+		textColor = UIRedColor
+	}
+	fmt.Println(color(textColor) + text + color(UIResetColor))
+}
+
+func ShowApprovalRequest(title string, arguments string) {
+	// show a function call waiting for approval
+	fmt.Println(color(UIYellowColor) + title + color(UIResetColor))
+	fmt.Println(arguments)
+}
+
 func ShowFunctions(prefix string, functions []string) {
 	// show available custom functions
 	fmt.Println(prefix)
@@ -108,3 +142,34 @@ func ShowFunctions(prefix string, functions []string) {
 	}
 	fmt.Println()
 }
+
+func ShowList(prefix string, lines []string) {
+	// show a list of lines
+	fmt.Println(prefix)
+	for _, line := range lines {
+		fmt.Println("- " + line)
+	}
+	fmt.Println()
+}
+
+func ShowQueue(prefix string, queued []string) {
+	// show queued prompts with their positions
+	fmt.Println(prefix)
+	for i, prompt := range queued {
+		fmt.Printf("%d. %s\n", i+1, prompt)
+	}
+	fmt.Println()
+}
+
+func SetColorEnabled(enabled bool) {
+	// enable or disable coloured output
+	colorEnabled = enabled
+}
+
+func color(code string) string {
+	// return color code when colours are enabled
+	if !colorEnabled {
+		return ""
+	}
+	return code
+}
@@ -0,0 +1,65 @@
+package clients
+
+func NewEventBus(buffer int) *EventBus {
+	// Create a fan-out event bus, each subscriber gets a channel with the given buffer
+	return &EventBus{
+		buffer: buffer,
+		closed: make(chan struct{}),
+	}
+}
+
+func (bus *EventBus) Subscribe() <-chan Event {
+	// Subscribe a new consumer, its channel receives every event published from now on until the bus is closed
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	subscriber := make(chan Event, bus.buffer)
+	select {
+	case <-bus.closed:
+		close(subscriber)
+	default:
+		bus.subscribers = append(bus.subscribers, subscriber)
+	}
+	return subscriber
+}
+
+func (bus *EventBus) Publish(event Event) {
+	// Deliver an event to every subscriber, waiting for slow subscribers unless the bus is closed
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	for _, subscriber := range bus.subscribers {
+		select {
+		case subscriber <- event:
+		case <-bus.closed:
+			return
+		}
+	}
+}
+
+func (bus *EventBus) TryPublish(event Event) {
+	// Deliver an event to every subscriber with room in its buffer, dropping it for the others
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	for _, subscriber := range bus.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+func (bus *EventBus) Close() {
+	// Close the bus, releasing blocked publishers and closing every subscriber channel
+	bus.closeOnce.Do(func() {
+		close(bus.closed)
+
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		for _, subscriber := range bus.subscribers {
+			close(subscriber)
+		}
+		bus.subscribers = nil
+	})
+}
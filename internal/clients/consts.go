@@ -0,0 +1,37 @@
+package clients
+
+const (
+	// Function call statuses
+	FunctionCallGenerating = "generating"
+	FunctionCallRunning = "running"
+	FunctionCallCompleted = "completed"
+	FunctionCallFailed = "failed"
+)
+
+const (
+	// Event types
+	TextDeltaEventType = "text.delta"
+	TextDoneEventType = "text.done"
+	ToolCallArgsDeltaEventType = "tool_call.args_delta"
+	ToolCallStartedEventType = "tool_call.started"
+	ToolCallCompletedEventType = "tool_call.completed"
+	ToolCallFailedEventType = "tool_call.failed"
+	ResponseStartedEventType = "response.started"
+	ResponseDoneEventType = "response.done"
+	ResponseCancelledEventType = "response.cancelled"
+	TurnDoneEventType = "turn.done"
+	QueuedMessageSentEventType = "queued_message.sent"
+	SessionUpdatedEventType = "session.updated"
+	SessionUpdateRejectedEventType = "session.update_rejected"
+	RateLimitsEventType = "rate_limits.updated"
+	BudgetWarningEventType = "budget.warning"
+	ConnectionStateEventType = "connection.state"
+)
+
+const (
+	// Connection states
+	ConnectionConnected = "connected"
+	ConnectionReconnecting = "reconnecting"
+	ConnectionDisconnected = "disconnected"
+	ConnectionLost = "lost"
+)
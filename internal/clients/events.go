@@ -0,0 +1,81 @@
+package clients
+
+func (event TextDeltaEvent) EventType() string {
+	// Return the text delta event type
+	return TextDeltaEventType
+}
+
+func (event TextDoneEvent) EventType() string {
+	// Return the text done event type
+	return TextDoneEventType
+}
+
+func (event ToolCallArgsDeltaEvent) EventType() string {
+	// Return the function call arguments delta event type
+	return ToolCallArgsDeltaEventType
+}
+
+func (event ToolCallStartedEvent) EventType() string {
+	// Return the function call started event type
+	return ToolCallStartedEventType
+}
+
+func (event ToolCallCompletedEvent) EventType() string {
+	// Return the function call completed event type
+	return ToolCallCompletedEventType
+}
+
+func (event ToolCallFailedEvent) EventType() string {
+	// Return the function call failed event type
+	return ToolCallFailedEventType
+}
+
+func (event ResponseStartedEvent) EventType() string {
+	// Return the response started event type
+	return ResponseStartedEventType
+}
+
+func (event ResponseDoneEvent) EventType() string {
+	// Return the response done event type
+	return ResponseDoneEventType
+}
+
+func (event ResponseCancelledEvent) EventType() string {
+	// Return the response cancelled event type
+	return ResponseCancelledEventType
+}
+
+func (event TurnDoneEvent) EventType() string {
+	// Return the turn done event type
+	return TurnDoneEventType
+}
+
+func (event QueuedMessageSentEvent) EventType() string {
+	// Return the queued message sent event type
+	return QueuedMessageSentEventType
+}
+
+func (event SessionUpdatedEvent) EventType() string {
+	// Return the session updated event type
+	return SessionUpdatedEventType
+}
+
+func (event SessionUpdateRejectedEvent) EventType() string {
+	// Return the session update rejected event type
+	return SessionUpdateRejectedEventType
+}
+
+func (event BudgetWarningEvent) EventType() string {
+	// Return the budget warning event type
+	return BudgetWarningEventType
+}
+
+func (event RateLimitsEvent) EventType() string {
+	// Return the rate limits event type
+	return RateLimitsEventType
+}
+
+func (event ConnectionStateEvent) EventType() string {
+	// Return the connection state event type
+	return ConnectionStateEventType
+}
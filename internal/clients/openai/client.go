@@ -1,11 +1,12 @@
 package openai
 
 import (
+	"RTGPTGoCLI/internal/budget"
 	"RTGPTGoCLI/internal/clients"
 	"RTGPTGoCLI/internal/common"
 	"RTGPTGoCLI/internal/config"
-	"RTGPTGoCLI/internal/functions"
 	"RTGPTGoCLI/internal/functions/handler"
+	"RTGPTGoCLI/internal/sessions"
 	"RTGPTGoCLI/pkg/errorhandler"
 	"RTGPTGoCLI/pkg/logger"
 	"context"
@@ -13,12 +14,13 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 )
 
 func NewOAIClient(cfg *config.Config, wsc clients.WebClientConnection) *OpenAIClient {
 	// Create new OpenAI client
 
-	functionHandler := handler.NewHandler()
+	functionHandler := handler.NewHandler(cfg)
 	if err := functionHandler.LoadFunctions(); err != nil {
 		logger.Warning(fmt.Sprintf(OAILoadFunctionsErr, err))
 	}
@@ -30,11 +32,23 @@ func NewOAIClient(cfg *config.Config, wsc clients.WebClientConnection) *OpenAICl
 		cleanUpOnce:      sync.Once{},
 
 		functionHandler: functionHandler,
+		conversation:     NewConversation(),
+		sessionSettings:  OAISessionSettings{
+			Model:        cfg.Model,
+			Instructions: sessionInstructions(cfg),
+			ToolChoice:   OAISessionToolsChoiceText,
+		},
+		clientEventTypes: make(map[string]string),
+		streamingCalls:   make(map[string]*clients.FunctionCallEvent),
+		sessionStore:     sessions.NewStore(cfg.GetSessionsDir()),
+		session:          sessions.NewSession(cfg.Model),
+		budget:           budget.NewTracker(cfg),
 		sessionID:        "",
 		responseID:       "",
 		isStreaming:      false,
 		
-		messageChannel:   make(chan clients.MessageEvent, cfg.ChannelBuffer),
+		closing:          make(chan struct{}),
+		events:           clients.NewEventBus(cfg.ChannelBuffer),
 		errorChannel:     make(chan errorhandler.AppError, cfg.ChannelBuffer),
 	}
 }
@@ -50,8 +64,10 @@ func (oaic *OpenAIClient) Connect(ctx context.Context) error {
 	}
 	logger.Debug(OAISessionCreatedMsg)
 
+	oaic.messageLoop.Add(1)
 	go oaic.processMessages(ctx)
 
+	oaic.events.Publish(clients.ConnectionStateEvent{State: clients.ConnectionConnected})
 	logger.Debug(OAIConnectedMsg)
 	return nil
 }
@@ -61,11 +77,26 @@ func (oaic *OpenAIClient) Disconnect() error {
 	var disconnectErr error
 	oaic.cleanUpOnce.Do(func() {
 		logger.Debug(OAIDisconnectingMsg)
+		oaic.functionHandler.Close()
+		defer oaic.events.Close()
 		if !oaic.IsConnected() {
 			return
 		}
 
-		close(oaic.messageChannel)
+		// Subscribers may have stopped reading on shutdown, so the last event never blocks
+		oaic.events.TryPublish(clients.ConnectionStateEvent{State: clients.ConnectionDisconnected})
+
+		// The message loop and running function calls report to the error channel, so they are stopped before it is closed
+		close(oaic.closing)
+		oaic.mu.RLock()
+		cancelTools := oaic.cancelTools
+		oaic.mu.RUnlock()
+		if cancelTools != nil {
+			cancelTools()
+		}
+		oaic.events.Close()
+		oaic.functionCalls.Wait()
+		oaic.messageLoop.Wait()
 		close(oaic.errorChannel)
 
 		if err := oaic.wsc.Disconnect(); err != nil {
@@ -78,7 +109,10 @@ func (oaic *OpenAIClient) Disconnect() error {
 
 func (oaic *OpenAIClient) IsConnected() bool {
 	// Return if OpenAI is connected
-	return oaic.wsc.IsConnected() && oaic.sessionID != ""
+	oaic.mu.RLock()
+	hasSession := oaic.sessionID != ""
+	oaic.mu.RUnlock()
+	return oaic.wsc.IsConnected() && hasSession
 }
 
 func (oaic *OpenAIClient) GetErrorChannel() <-chan errorhandler.AppError {
@@ -86,17 +120,218 @@ func (oaic *OpenAIClient) GetErrorChannel() <-chan errorhandler.AppError {
 	return oaic.errorChannel
 }
 
-func (oaic *OpenAIClient) GetMessageChannel() <-chan clients.MessageEvent {
-	// Return message channel
-	return oaic.messageChannel
+func (oaic *OpenAIClient) GetMessageChannel() <-chan clients.Event {
+	// Subscribe to the client events, every call returns a new channel that must be read until it is closed on disconnect
+	return oaic.events.Subscribe()
 }
 
 func (oaic *OpenAIClient) SendMessage(ctx context.Context, message string) *errorhandler.AppError {
-	// Send message to OpenAI
+	// Send message to OpenAI, queueing it while a response is still in progress, unless a budget is used up
+	if err := oaic.budget.Check(oaic.GetSession().GetUsage()); err != nil {
+		return errorhandler.NewAppError(errorhandler.WarningLevel, err.Error(), err)
+	}
+
+	oaic.mu.Lock()
+	if oaic.isStreaming || len(oaic.inputQueue) > 0 {
+		oaic.inputQueue = append(oaic.inputQueue, message)
+		queueLength := len(oaic.inputQueue)
+		oaic.mu.Unlock()
+		logger.Debug(fmt.Sprintf(OAIMessageQueuedMsg, queueLength))
+		return nil
+	}
+	oaic.isStreaming = true
+	oaic.mu.Unlock()
+
+	return oaic.sendUserMessage(ctx, message)
+}
+
+func (oaic *OpenAIClient) GetBudgetStatuses() ([]budget.Status, error) {
+	// Return the usage of the session, of today and of the profile against their budgets
+	return oaic.budget.GetStatuses(oaic.GetSession().GetUsage())
+}
+
+func (oaic *OpenAIClient) OverrideBudget() {
+	// Keep accepting prompts over budget for the rest of the run
+	oaic.budget.Override()
+}
+
+func (oaic *OpenAIClient) IsBudgetOverridden() bool {
+	// Return if prompts are accepted over budget
+	return oaic.budget.IsOverridden()
+}
+
+func (oaic *OpenAIClient) GetQueuedMessages() []string {
+	// Return the prompts waiting for the current response to finish
+	oaic.mu.RLock()
+	defer oaic.mu.RUnlock()
+	queued := make([]string, len(oaic.inputQueue))
+	copy(queued, oaic.inputQueue)
+	return queued
+}
+
+func (oaic *OpenAIClient) DropQueuedMessage(index int) bool {
+	// Drop a queued prompt by its index
+	oaic.mu.Lock()
+	defer oaic.mu.Unlock()
+	if index < 0 || index >= len(oaic.inputQueue) {
+		return false
+	}
+	oaic.inputQueue = append(oaic.inputQueue[:index], oaic.inputQueue[index+1:]...)
+	return true
+}
+
+func (oaic *OpenAIClient) ClearQueue() int {
+	// Drop all queued prompts, returning how many were dropped
+	oaic.mu.Lock()
+	defer oaic.mu.Unlock()
+	dropped := len(oaic.inputQueue)
+	oaic.inputQueue = nil
+	return dropped
+}
+
+func (oaic *OpenAIClient) CancelResponse(ctx context.Context) *errorhandler.AppError {
+	// Cancel the in-flight response and truncate the partially shown assistant item
+	if !oaic.getIsStreaming() {
+		return nil
+	}
+
+	oaic.mu.Lock()
+	responseID := oaic.responseID
+	itemID := oaic.streamingItemID
+	shownText := oaic.streamedText.String()
+	cancelTools := oaic.cancelTools
+	oaic.cancelledResponseID = responseID
+	oaic.cancelTools = nil
+	oaic.mu.Unlock()
+
+	if cancelTools != nil {
+		// The response is already done, only its function calls are still running
+		cancelTools()
+		oaic.setIsStreaming(false)
+		logger.Debug(fmt.Sprintf(OAIFunctionCallsCancelledMsg, responseID))
+		oaic.events.Publish(clients.ResponseCancelledEvent{ResponseID: responseID})
+		return nil
+	}
+
+	cancelPayload := OAIResponseCancelPayload{
+		Type:       OAIResponseCancelEventType,
+		EventID:    oaic.newClientEventID(OAIResponseCancelEventType),
+		ResponseID: responseID,
+	}
+
+	if appErr := oaic.sendToWebSocket(ctx, cancelPayload); appErr != nil {
+		return appErr
+	}
+
+	if itemID != "" {
+		truncatePayload := OAIConversationItemTruncatePayload{
+			Type:         OAIConversationItemTruncateEventType,
+			EventID:      oaic.newClientEventID(OAIConversationItemTruncateEventType),
+			ItemID:       itemID,
+			ContentIndex: 0,
+			AudioEndMs:   0,
+		}
+
+		if appErr := oaic.sendToWebSocket(ctx, truncatePayload); appErr != nil {
+			return appErr
+		}
+		oaic.conversation.TruncateItem(itemID, shownText)
+		if item, exists := oaic.conversation.GetItem(itemID); exists {
+			oaic.recordItem(item)
+		}
+	}
+
+	oaic.setIsStreaming(false)
+	logger.Debug(fmt.Sprintf(OAIResponseCancelledMsg, responseID))
+	oaic.events.Publish(clients.ResponseCancelledEvent{ResponseID: responseID})
+	return nil
+}
+
+func (oaic *OpenAIClient) IsStreaming() bool {
+	// Return if a response is currently in flight
+	return oaic.getIsStreaming()
+}
+
+func (oaic *OpenAIClient) GetSession() *sessions.Session {
+	// Return the chat session recorded by the client
+	oaic.mu.RLock()
+	defer oaic.mu.RUnlock()
+	return oaic.session
+}
+
+func (oaic *OpenAIClient) ResumeSession(ctx context.Context, sessionID string) *errorhandler.AppError {
+	// Resume a saved session, replaying its history into a fresh realtime session
 	if oaic.getIsStreaming() {
-		return errorhandler.NewAppError(errorhandler.WarningLevel, OAIMessageStreamInProgressMsg, nil)
+		return errorhandler.NewAppError(errorhandler.WarningLevel, OAIResumeWhileStreamingErr, errors.New(OAIResumeWhileStreamingErr))
+	}
+
+	session, err := oaic.sessionStore.Load(sessionID)
+	if err != nil {
+		return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAIResumeSessionErr, err), err)
+	}
+
+	if len(oaic.conversation.GetItems()) > 0 {
+		if appErr := oaic.reconnect(ctx); appErr != nil {
+			return appErr
+		}
+	}
+
+	oaic.mu.Lock()
+	oaic.session = session
+	oaic.mu.Unlock()
+
+	if appErr := oaic.replayItems(ctx, session.GetItems()); appErr != nil {
+		return appErr
+	}
+
+	logger.Debug(fmt.Sprintf(OAISessionResumedMsg, session.ID, session.TurnCount()))
+	return nil
+}
+
+func (oaic *OpenAIClient) GetConversation() []OAIConversationItem {
+	// Return a snapshot of the conversation items in order
+	return oaic.conversation.GetItems()
+}
+
+func (oaic *OpenAIClient) GetAvailableFunctions() []string {
+	// Return available custom functions, sorted by name
+	tools := oaic.functionHandler.GetTools()
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+func (oaic *OpenAIClient) GetToolPolicy(name string) string {
+	// Return the approval policy of a tool
+	return oaic.functionHandler.GetPolicy(name)
+}
+
+func (oaic *OpenAIClient) SetApprover(approver handler.Approver) {
+	// Set who approves the calls of tools with the "ask" policy
+	oaic.functionHandler.SetApprover(approver)
+}
+
+func (oaic *OpenAIClient) sendToWebSocket(ctx context.Context, payload interface{}) *errorhandler.AppError {
+	// Send payload to WebSocket
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return common.NewErrJsonMarshalAppError(err)
 	}
 
+	if err := oaic.wsc.SendMessage(ctx, payloadBytes); err != nil {
+		return errorhandler.NewAppError(errorhandler.WarningLevel, OAISendMessageErr, err)
+	}
+	return nil
+}
+
+func (oaic *OpenAIClient) sendUserMessage(ctx context.Context, message string) *errorhandler.AppError {
+	// Send user message as a conversation item and ask for a response
+	oaic.mu.Lock()
+	oaic.toolIterations = 0
+	oaic.mu.Unlock()
+
 	conversationItem := OAIConversationPayload{
 		Type: OAIConversationItemCreateEventType,
 		Item: OAIConversationItemMetadata{
@@ -112,57 +347,105 @@ func (oaic *OpenAIClient) SendMessage(ctx context.Context, message string) *erro
 	}
 
 	if appErr := oaic.sendToWebSocket(ctx, conversationItem); appErr != nil {
+		oaic.setIsStreaming(false)
 		return appErr
 	}
 
-	messagePayload := OAIResponsePayload{
-		Type: OAIResponseCreateEventType,
-		Response: OAIResponseMetadata{
-			Instructions: message,
-		},
-	}
-
-	if appErr := oaic.sendToWebSocket(ctx, messagePayload); appErr != nil {
+	if appErr := oaic.requestResponse(ctx); appErr != nil {
+		oaic.setIsStreaming(false)
 		return appErr
 	}
 
 	return nil
 }
 
-func (oaic *OpenAIClient) GetAvailableFunctions() []string {
-	// Return available custom functions
-	tools := oaic.functionHandler.GenerateOpenAITools()
-	names := make([]string, len(tools))
-	for i, tool := range tools {
-		names[i] = tool.(map[string]interface{})[OAIFunctionFieldName].(string)
+func (oaic *OpenAIClient) sendNextQueued(ctx context.Context) {
+	// Send the next queued prompt once the previous response is done, dropping the queue once a budget is used up
+	oaic.mu.Lock()
+	if oaic.isStreaming || len(oaic.inputQueue) == 0 {
+		oaic.mu.Unlock()
+		return
+	}
+	oaic.mu.Unlock()
+
+	if err := oaic.budget.Check(oaic.GetSession().GetUsage()); err != nil {
+		dropped := oaic.ClearQueue()
+		oaic.events.Publish(clients.BudgetWarningEvent{Message: fmt.Sprintf(OAIQueuedPromptsDroppedErr, err, dropped), Reached: true})
+		return
+	}
+
+	oaic.mu.Lock()
+	if oaic.isStreaming || len(oaic.inputQueue) == 0 {
+		oaic.mu.Unlock()
+		return
+	}
+	message := oaic.inputQueue[0]
+	oaic.inputQueue = oaic.inputQueue[1:]
+	oaic.isStreaming = true
+	oaic.mu.Unlock()
+
+	oaic.events.Publish(clients.QueuedMessageSentEvent{Text: message})
+	if appErr := oaic.sendUserMessage(ctx, message); appErr != nil {
+		oaic.reportError(appErr)
 	}
-	return names
 }
 
-func (oaic *OpenAIClient) sendToWebSocket(ctx context.Context, payload interface{}) *errorhandler.AppError {
-	// Send payload to WebSocket
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return common.NewErrJsonMarshalAppError(err)
+func (oaic *OpenAIClient) reconnect(ctx context.Context) *errorhandler.AppError {
+	// Start a fresh realtime session on a new connection, keeping message processing running
+	oaic.mu.Lock()
+	oaic.sessionID = ""
+	oaic.mu.Unlock()
+	oaic.conversation.Reset()
+
+	oaic.events.Publish(clients.ConnectionStateEvent{State: clients.ConnectionReconnecting})
+	if err := oaic.wsc.Reconnect(ctx); err != nil {
+		oaic.events.Publish(clients.ConnectionStateEvent{State: clients.ConnectionLost, Error: err.Error()})
+		return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAIReconnectErr, err), err)
 	}
 
-	if err := oaic.wsc.SendMessage(ctx, payloadBytes); err != nil {
-		return errorhandler.NewAppError(errorhandler.WarningLevel, OAISendMessageErr, err)
+	oaic.events.Publish(clients.ConnectionStateEvent{State: clients.ConnectionConnected})
+	return oaic.sendSessionConfig(ctx)
+}
+
+func (oaic *OpenAIClient) replayItems(ctx context.Context, items []sessions.Item) *errorhandler.AppError {
+	// Replay recorded items into the realtime conversation, keeping their IDs
+	for _, item := range items {
+		replayPayload := OAIConversationItemCreatePayload{
+			Type: OAIConversationItemCreateEventType,
+			Item: fromSessionItem(item),
+		}
+
+		if appErr := oaic.sendToWebSocket(ctx, replayPayload); appErr != nil {
+			return appErr
+		}
 	}
 	return nil
 }
 
+func (oaic *OpenAIClient) requestResponse(ctx context.Context) *errorhandler.AppError {
+	// Ask for a new response, keeping the session instructions untouched
+	responsePayload := OAIResponsePayload{
+		Type: OAIResponseCreateEventType,
+	}
+
+	return oaic.sendToWebSocket(ctx, responsePayload)
+}
+
 func (oaic *OpenAIClient) sendSessionConfig(ctx context.Context) *errorhandler.AppError {
-	// Define and send session config
+	// Define and send the full session config from the current settings
+	settings := oaic.getSessionSettings()
 	tools := oaic.functionHandler.GenerateOpenAITools()
 	sessionConfigPayload := OAISessionConfigPayload{
 		Type: OAISessionUpdateEventType,
+		EventID: oaic.newClientEventID(OAISessionUpdateEventType),
 		Session: OAISessionConfigMetadata{
 			Type: OAISessionTypeRealtimeText,
 			OutputModalities:    []string{OAISessionModalitiesText}, 
-			Instructions:  OAISessionInstructionsText,
+			Instructions:  settings.Instructions,
 			Tools:         tools,
-			ToolChoice:   OAISessionToolsChoiceText,
+			ToolChoice:   settings.ToolChoice,
+			Temperature:     settings.Temperature,
+			MaxOutputTokens: settings.MaxOutputTokens,
 		},
 	}
 
@@ -173,6 +456,24 @@ func (oaic *OpenAIClient) sendSessionConfig(ctx context.Context) *errorhandler.A
 	return nil
 }
 
+func (oaic *OpenAIClient) newClientEventID(eventType string) string {
+	// Tag a client event with an ID, so errors it causes are reported without ending the app
+	oaic.mu.Lock()
+	defer oaic.mu.Unlock()
+	oaic.clientEventCount++
+	eventID := fmt.Sprintf(OAIClientEventIDFormat, oaic.clientEventCount)
+	oaic.clientEventTypes[eventID] = eventType
+	return eventID
+}
+
+func (oaic *OpenAIClient) getClientEventType(eventID string) (string, bool) {
+	// Return the type of a tagged client event
+	oaic.mu.RLock()
+	defer oaic.mu.RUnlock()
+	eventType, exists := oaic.clientEventTypes[eventID]
+	return eventType, exists
+}
+
 func (oaic *OpenAIClient) getIsStreaming() bool {
 	// Return if OpenAI is streaming
 	oaic.mu.RLock()
@@ -188,21 +489,21 @@ func (oaic *OpenAIClient) setIsStreaming(status bool) {
 }
 
 func (oaic *OpenAIClient) processMessages(ctx context.Context) {
-	// Process messages from WebSocket
+	// Process messages from WebSocket until the context ends or the client is disconnecting
+	defer oaic.messageLoop.Done()
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-oaic.closing:
+			return
 		case err := <-oaic.wsc.GetErrorChannel():
-			oaic.errorChannel <- err
-		default:
-			msg, ok := <-oaic.wsc.GetMessageChannel()
+			oaic.reportError(&err)
+		case msg, ok := <-oaic.wsc.GetMessageChannel():
 			if !ok {
-				switch {
-				case ctx.Done() != nil:
-					return
+				if ctx.Err() == nil && !oaic.isClosing() {
+					oaic.reportError(errorhandler.NewAppError(errorhandler.ErrorLevel, OAIDisconnectedMsg, nil))
 				}
-				oaic.errorChannel <- *errorhandler.NewAppError(errorhandler.ErrorLevel, OAIDisconnectedMsg, nil)
 				return
 			}
 			oaic.handleEvent(ctx, msg)
@@ -214,7 +515,7 @@ func (oaic *OpenAIClient) handleEvent(ctx context.Context, event []byte) {
 	// Handle event from OpenAI
 	var messageType OAIStreamingEvent
 	if err := json.Unmarshal(event, &messageType); err != nil {
-		oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
 	
@@ -222,16 +523,22 @@ func (oaic *OpenAIClient) handleEvent(ctx context.Context, event []byte) {
 	switch msgType {
 	case OAISessionCreatedEventType:
 		oaic.handleSessionCreated(event)
-	case OAISessionUpdateEventType:
-		oaic.handleSessionUpdate(event)
+	case OAISessionUpdatedEventType:
+		oaic.handleSessionUpdated(event)
 	case OAIResponseCreatedEventType:
 		oaic.handleResponseCreated(event)
 	case OAIResponseDeltaEventType:
 		oaic.handleResponseDelta(event)
-	case OAIResponseDeltaDoneEventType, OAIResponseDoneEventType, OAIResponseOutputItemDoneEventType, OAIConversationItemDoneEventType, OAIFunctionCallDoneEventType:
+	case OAIResponseOutputItemAddedEventType:
+		oaic.handleOutputItemAdded(event)
+	case OAIFunctionCallDeltaEventType:
+		oaic.handleFunctionCallDelta(event)
+	case OAIRateLimitsUpdatedEventType:
+		oaic.handleRateLimitsUpdated(event)
+	case OAIResponseDeltaDoneEventType, OAIResponseDoneEventType, OAIResponseOutputItemDoneEventType, OAIConversationItemDoneEventType:
 		oaic.handleResponseDone(ctx, msgType, event)
 	case OAIResponseFailedEventType, OAIResponseErrorEventType:
-		oaic.handleResponseError(event)
+		oaic.handleResponseError(ctx, event)
 	}
 }
 
@@ -239,135 +546,454 @@ func (oaic *OpenAIClient) handleSessionCreated(msg []byte) {
 	// Handle session created event
 	var created OAISessionCreatedEventPayload
 	if err := json.Unmarshal(msg, &created); err != nil {
-		oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
+	oaic.mu.Lock()
 	oaic.sessionID = created.Session.ID
-	logger.Debug(fmt.Sprintf(OAISessionCreatedWithIDMsg, oaic.sessionID))
-}
-
-func (oaic *OpenAIClient) handleSessionUpdate(msg []byte) {
-	// Handle session update event
-	logger.Debug(OAISessionUpdatedMsg)
+	oaic.mu.Unlock()
+	logger.Debug(fmt.Sprintf(OAISessionCreatedWithIDMsg, created.Session.ID))
 }
 
 func (oaic *OpenAIClient) handleResponseCreated(msg []byte) {
 	// Handle response created event
 	var created OAIResponseCreatedEventPayload
 	if err := json.Unmarshal(msg, &created); err != nil {
-		oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
+	oaic.mu.Lock()
 	oaic.responseID = created.Response.ID
-	logger.Debug(fmt.Sprintf(OAIResponseCreatedWithIDMsg, oaic.responseID))
+	oaic.mu.Unlock()
+
+	oaic.setIsStreaming(true)
+	logger.Debug(fmt.Sprintf(OAIResponseCreatedWithIDMsg, created.Response.ID))
+	oaic.events.Publish(clients.ResponseStartedEvent{ResponseID: created.Response.ID})
 }
 
 func (oaic *OpenAIClient) handleResponseDelta(msg []byte) {
 	// Handle response delta event, returning deltas to simulate chat streaming
+	var delta OAIResponseOutPutTextDeltaPayload
+	if err := json.Unmarshal(msg, &delta); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
+		return
+	}
+
+	// Late deltas of a cancelled response must not mark the client as streaming again
+	if oaic.isCancelledResponse(delta.ResponseID) {
+		return
+	}
+
 	if !oaic.getIsStreaming() {
 		oaic.setIsStreaming(true)
 	}
-	
-	var delta OAIResponseOutPutTextDeltaPayload
+
+	oaic.mu.Lock()
+	if delta.ItemId != oaic.streamingItemID {
+		oaic.streamingItemID = delta.ItemId
+		oaic.streamedText.Reset()
+	}
+	oaic.streamedText.WriteString(delta.Delta)
+	oaic.mu.Unlock()
+
+	oaic.events.Publish(clients.TextDeltaEvent{ResponseID: delta.ResponseID, ItemID: delta.ItemId, Delta: delta.Delta})
+}
+
+func (oaic *OpenAIClient) handleOutputItemAdded(msg []byte) {
+	// Handle output item added event, tracking function calls whose arguments are about to stream
+	var itemAdded OAIConversationItemDonePayload
+	if err := json.Unmarshal(msg, &itemAdded); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
+		return
+	}
+
+	if itemAdded.Item.Type != OAIFunctionCallItemType || oaic.isCancelledResponse(itemAdded.ResponseID) {
+		return
+	}
+
+	oaic.mu.Lock()
+	oaic.streamingCalls[itemAdded.Item.ID] = &clients.FunctionCallEvent{
+		CallID: itemAdded.Item.CallID,
+		Name:   itemAdded.Item.Name,
+		Status: clients.FunctionCallGenerating,
+	}
+	oaic.mu.Unlock()
+}
+
+func (oaic *OpenAIClient) handleFunctionCallDelta(msg []byte) {
+	// Handle function call arguments delta event, emitting the arguments generated so far
+	var delta OAIFunctionCallDeltaPayload
 	if err := json.Unmarshal(msg, &delta); err != nil {
-		oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
-	oaic.messageChannel <- clients.MessageEvent{Type: OAIResponseDeltaEventType, Text: delta.Delta, Done: false}
+
+	if oaic.isCancelledResponse(delta.ResponseID) {
+		return
+	}
+
+	oaic.mu.Lock()
+	call, exists := oaic.streamingCalls[delta.ItemID]
+	if !exists {
+		call = &clients.FunctionCallEvent{CallID: delta.CallID, Status: clients.FunctionCallGenerating}
+		oaic.streamingCalls[delta.ItemID] = call
+	}
+	call.Arguments += delta.Delta
+	functionEvent := *call
+	oaic.mu.Unlock()
+
+	oaic.events.Publish(clients.ToolCallArgsDeltaEvent{ResponseID: delta.ResponseID, Delta: delta.Delta, Call: functionEvent})
+}
+
+func (oaic *OpenAIClient) handleRateLimitsUpdated(msg []byte) {
+	// Handle rate limits updated event
+	var rateLimits OAIRateLimitsUpdatedPayload
+	if err := json.Unmarshal(msg, &rateLimits); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
+		return
+	}
+	oaic.events.Publish(clients.RateLimitsEvent{Limits: rateLimits.RateLimits})
 }
 
 func (oaic *OpenAIClient) handleResponseDone(ctx context.Context, msgType string, msg []byte) {
 	// Handle response done event
+	var responseEvent OAIResponseEventMetadata
+	if err := json.Unmarshal(msg, &responseEvent); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
+		return
+	}
+
 	switch msgType {
 	case OAIResponseDeltaDoneEventType:
-		oaic.messageChannel <- clients.MessageEvent{Type: OAIResponseDeltaDoneEventType, Text: "", Done: true}
-	case OAIFunctionCallDoneEventType:
-		oaic.handleFunctionCallDone(ctx, msg)
+		if oaic.isCancelledResponse(responseEvent.ResponseID) {
+			return
+		}
+		oaic.events.Publish(clients.TextDoneEvent{ResponseID: responseEvent.ResponseID, Text: responseEvent.Text})
+	case OAIConversationItemDoneEventType, OAIResponseOutputItemDoneEventType:
+		oaic.handleConversationItemDone(msgType, msg)
+	case OAIResponseDoneEventType:
+		oaic.handleTurnDone(ctx, msg)
 	}
-	oaic.setIsStreaming(false)
 }
 
-func (oaic *OpenAIClient) handleFunctionCallDone(ctx context.Context, msg []byte) {
-	// Handle function call done event
-	var functionCallDone OAIFunctionCallDonePayload
-
-	if err := json.Unmarshal(msg, &functionCallDone); err != nil {
-		oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+func (oaic *OpenAIClient) handleTurnDone(ctx context.Context, msg []byte) {
+	// Handle response.done, completing the turn unless a function call continuation is pending
+	var responseDone OAIResponseDonePayload
+	if err := json.Unmarshal(msg, &responseDone); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
 
-	logger.Debug(fmt.Sprintf(OAIExecutingFunctionWithArgsMsg, functionCallDone.Name, functionCallDone.Arguments))
+	usage := responseDone.Response.Usage.toUsageEvent()
+	if usage != nil {
+		oaic.recordUsage(responseDone.Response.ID, usage)
+	}
 
-	result, appErr := oaic.functionHandler.Execute(ctx, functionCallDone.Name, functionCallDone.Arguments)
-	if appErr != nil {
-		oaic.errorChannel <- *appErr
+	oaic.events.Publish(clients.ResponseDoneEvent{
+		ResponseID: responseDone.Response.ID,
+		Status:     responseDone.Response.Status,
+		Usage:      usage,
+	})
+
+	if responseDone.Response.Status == OAIResponseStatusFailed {
+		statusError := responseDone.Response.StatusDetails.Error
+		errorMsg := fmt.Sprintf(OAIFailedResponseErr, statusError.Code, statusError.Message)
+		oaic.reportError(errorhandler.NewAppError(errorhandler.ErrorLevel, errorMsg, errors.New(OAIFailedResponseErr)))
+	}
+
+	oaic.mu.Lock()
+	oaic.streamingItemID = ""
+	oaic.streamedText.Reset()
+	oaic.streamingCalls = make(map[string]*clients.FunctionCallEvent)
+
+	cancelled := responseDone.Response.ID != "" && responseDone.Response.ID == oaic.cancelledResponseID
+	calls := responseDone.Response.functionCalls()
+	if len(calls) > 0 && !cancelled && responseDone.Response.Status == OAIResponseStatusCompleted {
+		oaic.toolIterations++
+		if oaic.toolIterations <= oaic.config.MaxToolIterations {
+			toolCtx, cancelTools := context.WithCancel(ctx)
+			oaic.cancelTools = cancelTools
+			oaic.functionCalls.Add(1)
+			oaic.mu.Unlock()
+			go oaic.runFunctionCalls(ctx, toolCtx, responseDone.Response.ID, calls)
+			return
+		}
+		oaic.mu.Unlock()
+		oaic.stopFunctionCalls(ctx, calls)
+		oaic.mu.Lock()
+	}
+
+	// A cancelled response only keeps the client streaming when a newer response already started
+	oaic.toolIterations = 0
+	if !cancelled || oaic.responseID == responseDone.Response.ID {
+		oaic.isStreaming = false
+	}
+	oaic.mu.Unlock()
+
+	logger.Debug(fmt.Sprintf(OAIResponseDoneWithStatusMsg, responseDone.Response.ID, responseDone.Response.Status))
+	oaic.events.Publish(clients.TurnDoneEvent{ResponseID: responseDone.Response.ID})
+	oaic.sendNextQueued(ctx)
+}
+
+func (oaic *OpenAIClient) isCancelledResponse(responseID string) bool {
+	// Return if the event belongs to a response cancelled by the user
+	oaic.mu.RLock()
+	defer oaic.mu.RUnlock()
+	return responseID != "" && responseID == oaic.cancelledResponseID
+}
+
+func (oaic *OpenAIClient) handleConversationItemDone(msgType string, msg []byte) {
+	// Handle conversation item done events, tracking the item in the conversation
+	var itemDone OAIConversationItemDonePayload
+	if err := json.Unmarshal(msg, &itemDone); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
-	
-	resultMap, ok := result.(functions.FunctionResponse)
-	if !ok {
-		oaic.errorChannel <- *errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAIUnexpectedFunctionResultType, result), nil)
+
+	if itemDone.Item.ID == "" {
 		return
 	}
 
-	resultToSend := fmt.Sprintf("%v", resultMap.Result)
-	oaic.sendFunctionResult(ctx, functionCallDone, resultToSend)
+	oaic.conversation.UpsertItem(itemDone.PreviousItemID, itemDone.Item)
+	logger.Debug(fmt.Sprintf(OAIConversationItemTrackedMsg, itemDone.Item.Type, itemDone.Item.ID, msgType))
+
+	if item, exists := oaic.conversation.GetItem(itemDone.Item.ID); exists {
+		oaic.recordItem(item)
+	}
+}
+
+func (oaic *OpenAIClient) recordItem(item OAIConversationItem) {
+	// Record conversation item in the chat session and persist it
+	session := oaic.GetSession()
+	session.UpsertItem(item.toSessionItem())
+	oaic.saveSession(session)
 }
 
-func (oaic *OpenAIClient) sendFunctionResult(ctx context.Context, functionData OAIFunctionCallDonePayload, result interface{}) {
-	// Send function result to OpenAI
-	functionResultPayload := OAIFunctionCallResultPayload{
-		Type: OAIConversationItemCreateEventType,
-		Item: OAIFunctionResultItemMetadata{
-			Type:   OAIFunctionCallResultText,
-			CallID: functionData.CallID,
-			Output: result,
-		},
+func (oaic *OpenAIClient) recordUsage(responseID string, usage *clients.UsageEvent) {
+	// Price the usage of a response with the model of the session, then record it in the chat session
+	model := oaic.getSessionSettings().Model
+	usage.Currency = oaic.config.Currency
+	if price, exists := oaic.config.GetPrice(model); exists {
+		cost := price.Cost(usage.InputTokens, usage.CachedTokens, usage.OutputTokens)
+		usage.Cost = &cost
+	}
+
+	responseUsage := sessions.ResponseUsage{
+		ResponseID:   responseID,
+		Model:        model,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		CachedTokens: usage.CachedTokens,
+		TotalTokens:  usage.TotalTokens,
+		Cost:         usage.Cost,
+		Currency:     usage.Currency,
+	}
+	session := oaic.GetSession()
+	session.AddResponseUsage(responseUsage)
+	oaic.saveSession(session)
+
+	warnings, err := oaic.budget.Record(responseUsage, session.GetUsage())
+	if err != nil {
+		oaic.reportError(errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAIRecordUsageErr, err), err))
+	}
+	for _, warning := range warnings {
+		oaic.events.Publish(clients.BudgetWarningEvent{Message: warning.Message, Reached: warning.Reached})
+	}
+}
+
+func (oaic *OpenAIClient) saveSession(session *sessions.Session) {
+	// Persist the chat session, reporting failures without ending the turn
+	if err := oaic.sessionStore.Save(session); err != nil {
+		oaic.reportError(errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAISaveSessionErr, err), err))
+	}
+}
+
+func (oaic *OpenAIClient) runFunctionCalls(ctx context.Context, toolCtx context.Context, responseID string, calls []OAIConversationItem) {
+	// Execute the function calls of a response off the event loop with bounded parallelism, then submit every output and request one continuation
+	defer oaic.functionCalls.Done()
+	outputs := make([]string, len(calls))
+	semaphore := make(chan struct{}, oaic.config.ToolParallelism)
+	var wg sync.WaitGroup
+
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			outputs[i] = oaic.executeFunctionCall(toolCtx, responseID, call)
+		}()
 	}
+	wg.Wait()
 
-	if appErr := oaic.sendToWebSocket(ctx, functionResultPayload); appErr != nil {
-		oaic.errorChannel <- *appErr
+	oaic.mu.Lock()
+	cancelTools := oaic.cancelTools
+	oaic.cancelTools = nil
+	oaic.mu.Unlock()
+
+	// CancelResponse takes the cancel function when the user cancels the calls
+	cancelled := cancelTools == nil
+	if !cancelled {
+		cancelTools()
+	}
+
+	if ctx.Err() != nil || oaic.isClosing() {
 		return
 	}
 
-	continueFunctionCallPayload := OAIFunctionResultContinuePayload{
-		Type: OAIResponseCreateEventType,
-		Response: FunctionContinueResponseMetadata{
-			Instructions: fmt.Sprintf(OAIFunctionCallInstructions, functionData.Name, functionData.Arguments, result),
-		},
+	for i, call := range calls {
+		if appErr := oaic.sendFunctionResult(ctx, call.CallID, outputs[i]); appErr != nil {
+			oaic.reportError(appErr)
+		}
 	}
 
-	if appErr := oaic.sendToWebSocket(ctx, continueFunctionCallPayload); appErr != nil {
-		oaic.errorChannel <- *appErr
+	if cancelled {
+		oaic.mu.Lock()
+		oaic.toolIterations = 0
+		oaic.mu.Unlock()
+		oaic.events.Publish(clients.TurnDoneEvent{ResponseID: responseID})
+		oaic.sendNextQueued(ctx)
 		return
 	}
+
+	if appErr := oaic.requestResponse(ctx); appErr != nil {
+		oaic.setIsStreaming(false)
+		oaic.reportError(appErr)
+	}
+}
+
+func (oaic *OpenAIClient) stopFunctionCalls(ctx context.Context, calls []OAIConversationItem) {
+	// Answer the calls of a turn over the iterations limit with an error, without asking for a continuation
+	appErr := errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAIMaxToolIterationsErr, oaic.config.MaxToolIterations), nil)
+	oaic.reportError(appErr)
+
+	output := functionErrorOutput(appErr)
+	for _, call := range calls {
+		if appErr := oaic.sendFunctionResult(ctx, call.CallID, output); appErr != nil {
+			oaic.reportError(appErr)
+		}
+	}
 }
 
-func (oaic *OpenAIClient) handleResponseError(event []byte) {
+func (oaic *OpenAIClient) executeFunctionCall(ctx context.Context, responseID string, call OAIConversationItem) string {
+	// Execute a function call, returning the output sent to the model
+	logger.Debug(fmt.Sprintf(OAIExecutingFunctionWithArgsMsg, call.Name, call.Arguments))
+
+	functionEvent := &clients.FunctionCallEvent{
+		CallID:    call.CallID,
+		Name:      call.Name,
+		Arguments: call.Arguments,
+		Status:    clients.FunctionCallRunning,
+	}
+
+	prepared, appErr := oaic.functionHandler.Prepare(ctx, call.Name, call.Arguments)
+	if appErr != nil {
+		return oaic.functionError(responseID, functionEvent, appErr)
+	}
+
+	oaic.events.Publish(clients.ToolCallStartedEvent{ResponseID: responseID, Call: *functionEvent})
+
+	startTime := time.Now()
+	result, appErr := prepared.Run(ctx)
+	functionEvent.DurationMs = time.Since(startTime).Milliseconds()
+	if appErr != nil {
+		return oaic.functionError(responseID, functionEvent, appErr)
+	}
+
+	output, err := result.Output()
+	if err != nil {
+		appErr := errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(OAIFunctionResultOutputErr, err), err)
+		return oaic.functionError(responseID, functionEvent, appErr)
+	}
+
+	functionEvent.Result = result
+	functionEvent.Status = clients.FunctionCallCompleted
+	oaic.events.Publish(clients.ToolCallCompletedEvent{ResponseID: responseID, Call: *functionEvent})
+	return output
+}
+
+func (oaic *OpenAIClient) functionError(responseID string, functionEvent *clients.FunctionCallEvent, appErr *errorhandler.AppError) string {
+	// Report a failed call, returning the error output so the assistant can explain or retry
+	functionEvent.Error = appErr.Message
+	functionEvent.Status = clients.FunctionCallFailed
+	oaic.events.Publish(clients.ToolCallFailedEvent{ResponseID: responseID, Call: *functionEvent})
+	oaic.reportError(appErr)
+	return functionErrorOutput(appErr)
+}
+
+func (oaic *OpenAIClient) reportError(appErr *errorhandler.AppError) {
+	// Report an error, dropping it once the client is disconnecting
+	select {
+	case oaic.errorChannel <- *appErr:
+	case <-oaic.closing:
+	}
+}
+
+func (oaic *OpenAIClient) isClosing() bool {
+	// Return if the client is disconnecting
+	select {
+	case <-oaic.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+func (oaic *OpenAIClient) sendFunctionResult(ctx context.Context, callID string, output string) *errorhandler.AppError {
+	// Send function call output to OpenAI
+	functionResultPayload := OAIFunctionCallResultPayload{
+		Type: OAIConversationItemCreateEventType,
+		Item: OAIFunctionResultItemMetadata{
+			Type:   OAIFunctionCallResultText,
+			CallID: callID,
+			Output: output,
+		},
+	}
+	return oaic.sendToWebSocket(ctx, functionResultPayload)
+}
+
+func (oaic *OpenAIClient) handleResponseError(ctx context.Context, event []byte) {
 	// Handle response error event by type of error
-	oaic.setIsStreaming(false)
-	var errorType OAIStreamingEvent
+	var errorType OAIResponseErrorPayload
 	if err := json.Unmarshal(event, &errorType); err != nil {
-		oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 		return
 	}
 
+	if clientEventType, exists := oaic.getClientEventType(errorType.Error.EventID); exists {
+		oaic.handleClientEventError(clientEventType, errorType.Error)
+		return
+	}
+
+	oaic.setIsStreaming(false)
+	defer oaic.sendNextQueued(ctx)
+
 	switch errorType.Type {
 	case OAIResponseFailedEventType:
 		var messageFailure OAIResponseFailedEventPayload
 		if err := json.Unmarshal(event, &messageFailure); err != nil {
-			oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+			oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 			return
 		}
 		errorMsg := fmt.Sprintf(OAIFailedResponseErr, messageFailure.Response.Error.Code, messageFailure.Response.Error.Message)
-		oaic.errorChannel <- *errorhandler.NewAppError(errorhandler.ErrorLevel, errorMsg, errors.New(OAIFailedResponseErr))
+		oaic.reportError(errorhandler.NewAppError(errorhandler.ErrorLevel, errorMsg, errors.New(OAIFailedResponseErr)))
 	case OAIResponseErrorEventType:
 		var messageError OAIResponseErrorPayload
 		if err := json.Unmarshal(event, &messageError); err != nil {
-			oaic.errorChannel <- *common.NewErrJsonUnmarshalAppError(err)
+			oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
 			return
 		}
 		errorMsg := fmt.Sprintf(OAIFailedResponseErr, messageError.Error.Code, messageError.Error.Message)
-		oaic.errorChannel <- *errorhandler.NewAppError(errorhandler.ErrorLevel, errorMsg, errors.New(OAIErrorResponseErr))
+		oaic.reportError(errorhandler.NewAppError(errorhandler.ErrorLevel, errorMsg, errors.New(OAIErrorResponseErr)))
+	}
+}
+
+func (oaic *OpenAIClient) handleClientEventError(clientEventType string, eventError OAIErrorMetadata) {
+	// Report an error caused by a tagged client event, keeping the response and app running
+	errorMsg := fmt.Sprintf(OAIClientEventErr, clientEventType, eventError.Code, eventError.Message)
+	oaic.reportError(errorhandler.NewAppError(errorhandler.WarningLevel, errorMsg, errors.New(errorMsg)))
+
+	if clientEventType == OAISessionUpdateEventType {
+		oaic.events.Publish(clients.SessionUpdateRejectedEvent{Reason: eventError.Message})
 	}
 }
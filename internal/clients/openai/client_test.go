@@ -0,0 +1,230 @@
+package openai
+
+import (
+	"RTGPTGoCLI/internal/clients"
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeWebClient struct {
+	// Web client connection recording the sent messages instead of using a websocket
+	mu           sync.Mutex
+	sent         []string
+	messages     chan []byte
+	errorChannel chan errorhandler.AppError
+}
+
+func newFakeWebClient() *fakeWebClient {
+	// Create fake web client
+	return &fakeWebClient{
+		messages:     make(chan []byte),
+		errorChannel: make(chan errorhandler.AppError),
+	}
+}
+
+func (fwc *fakeWebClient) Connect(ctx context.Context) error {
+	// Connect nothing
+	return nil
+}
+
+func (fwc *fakeWebClient) Disconnect() error {
+	// Disconnect nothing
+	return nil
+}
+
+func (fwc *fakeWebClient) IsConnected() bool {
+	// Always connected
+	return true
+}
+
+func (fwc *fakeWebClient) GetErrorChannel() <-chan errorhandler.AppError {
+	// Return error channel
+	return fwc.errorChannel
+}
+
+func (fwc *fakeWebClient) Reconnect(ctx context.Context) error {
+	// Reconnect nothing
+	return nil
+}
+
+func (fwc *fakeWebClient) SetModel(model string) {
+	// Ignore the model
+}
+
+func (fwc *fakeWebClient) SendMessage(ctx context.Context, message []byte) error {
+	// Record a sent message
+	fwc.mu.Lock()
+	defer fwc.mu.Unlock()
+	fwc.sent = append(fwc.sent, string(message))
+	return nil
+}
+
+func (fwc *fakeWebClient) GetMessageChannel() <-chan []byte {
+	// Return message channel
+	return fwc.messages
+}
+
+func (fwc *fakeWebClient) sentContaining(text string) int {
+	// Count the sent messages containing text
+	fwc.mu.Lock()
+	defer fwc.mu.Unlock()
+	count := 0
+	for _, message := range fwc.sent {
+		if strings.Contains(message, text) {
+			count++
+		}
+	}
+	return count
+}
+
+func newTestClient(t *testing.T) (*OpenAIClient, *fakeWebClient) {
+	// Create a client on a fake web client, with its data and tools in a temp directory
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Model:             "test-model",
+		ChannelBuffer:     16,
+		DataDir:           dir,
+		ToolsDir:          dir,
+		ToolTimeout:       1,
+		ToolParallelism:   1,
+		MaxToolIterations: 1,
+		ToolPolicy:        config.DefaultToolPolicy,
+		BudgetWarning:     config.DefaultBudgetWarning,
+	}
+	wsc := newFakeWebClient()
+	return NewOAIClient(cfg, wsc), wsc
+}
+
+func TestQueuedPromptIsSentAfterCancelWithStaleDelta(t *testing.T) {
+	// Replay cancel, a late delta and the cancelled response.done, the queued prompt must still be sent
+	ctx := context.Background()
+	oaic, wsc := newTestClient(t)
+
+	if appErr := oaic.SendMessage(ctx, "first prompt"); appErr != nil {
+		t.Fatalf("SendMessage() error = %v", appErr.Message)
+	}
+	oaic.handleEvent(ctx, []byte(`{"type":"response.created","response":{"id":"resp_1","status":"in_progress"}}`))
+	oaic.handleEvent(ctx, []byte(`{"type":"response.output_text.delta","response_id":"resp_1","item_id":"item_1","delta":"Hel"}`))
+
+	if appErr := oaic.SendMessage(ctx, "queued prompt"); appErr != nil {
+		t.Fatalf("SendMessage() error = %v", appErr.Message)
+	}
+	if queued := oaic.GetQueuedMessages(); len(queued) != 1 {
+		t.Fatalf("queued prompts = %v, want the prompt sent while streaming", queued)
+	}
+
+	if appErr := oaic.CancelResponse(ctx); appErr != nil {
+		t.Fatalf("CancelResponse() error = %v", appErr.Message)
+	}
+	if wsc.sentContaining(OAIResponseCancelEventType) != 1 {
+		t.Fatalf("response.cancel was not sent")
+	}
+
+	oaic.handleEvent(ctx, []byte(`{"type":"response.output_text.delta","response_id":"resp_1","item_id":"item_1","delta":"lo"}`))
+	if oaic.IsStreaming() {
+		t.Fatalf("a late delta of the cancelled response marked the client as streaming")
+	}
+
+	oaic.handleEvent(ctx, []byte(`{"type":"response.done","response":{"id":"resp_1","status":"cancelled","output":[]}}`))
+
+	if queued := oaic.GetQueuedMessages(); len(queued) != 0 {
+		t.Fatalf("queued prompts = %v, want the queued prompt sent", queued)
+	}
+	if wsc.sentContaining("queued prompt") != 1 {
+		t.Fatalf("the queued prompt was not sent")
+	}
+	if !oaic.IsStreaming() {
+		t.Fatalf("the client is not streaming the response of the queued prompt")
+	}
+}
+
+func TestCancelledDoneKeepsNewerResponseStreaming(t *testing.T) {
+	// A late response.done of a cancelled response doesn't end a newer response
+	ctx := context.Background()
+	oaic, _ := newTestClient(t)
+
+	if appErr := oaic.SendMessage(ctx, "first prompt"); appErr != nil {
+		t.Fatalf("SendMessage() error = %v", appErr.Message)
+	}
+	oaic.handleEvent(ctx, []byte(`{"type":"response.created","response":{"id":"resp_1","status":"in_progress"}}`))
+	if appErr := oaic.CancelResponse(ctx); appErr != nil {
+		t.Fatalf("CancelResponse() error = %v", appErr.Message)
+	}
+
+	if appErr := oaic.SendMessage(ctx, "second prompt"); appErr != nil {
+		t.Fatalf("SendMessage() error = %v", appErr.Message)
+	}
+	oaic.handleEvent(ctx, []byte(`{"type":"response.created","response":{"id":"resp_2","status":"in_progress"}}`))
+	oaic.handleEvent(ctx, []byte(`{"type":"response.done","response":{"id":"resp_1","status":"cancelled","output":[]}}`))
+
+	if !oaic.IsStreaming() {
+		t.Fatalf("the cancelled response.done ended the newer response")
+	}
+}
+
+func TestQueuedPromptIsRefusedOverBudget(t *testing.T) {
+	// A prompt queued during the response that uses up the budget is dropped instead of sent
+	ctx := context.Background()
+	oaic, wsc := newTestClient(t)
+	oaic.config.Budgets.Session = config.BudgetConfig{Tokens: 100}
+	events := oaic.GetMessageChannel()
+
+	if appErr := oaic.SendMessage(ctx, "first prompt"); appErr != nil {
+		t.Fatalf("SendMessage() error = %v", appErr.Message)
+	}
+	oaic.handleEvent(ctx, []byte(`{"type":"response.created","response":{"id":"resp_1","status":"in_progress"}}`))
+	if appErr := oaic.SendMessage(ctx, "queued prompt"); appErr != nil {
+		t.Fatalf("SendMessage() error = %v", appErr.Message)
+	}
+	oaic.handleEvent(ctx, []byte(`{"type":"response.done","response":{"id":"resp_1","status":"completed","output":[],"usage":{"total_tokens":150,"input_tokens":100,"output_tokens":50}}}`))
+
+	if wsc.sentContaining("queued prompt") != 0 {
+		t.Fatalf("the queued prompt was sent over budget")
+	}
+	if queued := oaic.GetQueuedMessages(); len(queued) != 0 {
+		t.Fatalf("queued prompts = %v, want them dropped", queued)
+	}
+
+	oaic.events.Close()
+	for event := range events {
+		if warning, ok := event.(clients.BudgetWarningEvent); ok && strings.Contains(warning.Message, "dropped 1 queued prompts") {
+			return
+		}
+	}
+	t.Fatalf("no budget error was published for the dropped prompt")
+}
+
+func TestDisconnectStopsMessageLoopBeforeClosingErrors(t *testing.T) {
+	// Disconnect while the message loop reports to an unread, full error channel, it must return without a send on the closed channel
+	ctx := context.Background()
+	oaic, wsc := newTestClient(t)
+
+	if err := oaic.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	wsc.messages <- []byte(`{"type":"session.created","session":{"id":"sess_1"}}`)
+	for i := 0; i < oaic.config.ChannelBuffer+1; i++ {
+		wsc.messages <- []byte(`not json`)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- oaic.Disconnect()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Disconnect() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Disconnect() did not return while the message loop was reporting an error")
+	}
+
+	for range oaic.GetErrorChannel() {
+	}
+}
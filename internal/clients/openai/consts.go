@@ -4,15 +4,18 @@ const (
 	// OpenAI events
 	OAISessionCreatedEventType     = "session.created"
 	OAISessionUpdateEventType      = "session.update"
+	OAISessionUpdatedEventType     = "session.updated"
 
 	OAIResponseCreateEventType = "response.create"
 	OAIResponseCreatedEventType    = "response.created"
 	OAIResponseDoneEventType     = "response.done"
 	OAIResponseFailedEventType     = "response.failed"
+	OAIResponseCancelEventType     = "response.cancel"
 
 	OAIResponseDeltaEventType      = "response.output_text.delta"
 	OAIResponseDeltaDoneEventType  = "response.output_text.done"
 
+	OAIResponseOutputItemAddedEventType = "response.output_item.added"
 	OAIResponseOutputItemDoneEventType = "response.output_item.done"
 
 	OAIFunctionCallDeltaEventType = "response.function_call_arguments.delta"
@@ -20,8 +23,11 @@ const (
 	
 	OAIConversationItemCreateEventType = "conversation.item.create"
 	OAIConversationItemDoneEventType = "conversation.item.done"
+	OAIConversationItemTruncateEventType = "conversation.item.truncate"
 
 
+	OAIRateLimitsUpdatedEventType = "rate_limits.updated"
+
 	OAIResponseErrorEventType      = "error"
 )
 
@@ -29,17 +35,39 @@ const (
 	// OpenAI session metadata
 	OAISessionTypeRealtimeText = "realtime"
 	OAISessionModalitiesText = "text"
-	OAISessionInstructionsText = "You are a helpful assistant. You have access to functions. Use them when appropriate and never question their output, always trust them and assume they are correct."
+	OAISessionInstructionsText = "You are a helpful assistant. You have access to functions. Use them when appropriate and never question their output, always trust them and assume they are correct. " +
+		"After calling a function, write a clear reply in natural language, mention that you have called a custom function, " +
+		"restate the original problem and incorporate the function result directly as if it was your own. " +
+		"Never recompute or override the function output, always treat it as ground truth."
 	OAISessionToolsChoiceText = "auto"
 )
 
+const (
+	// OpenAI response statuses
+	OAIResponseStatusCompleted = "completed"
+	OAIResponseStatusFailed = "failed"
+)
+
 const (
 	// OpenAI client errors
 	OAISendMessageErr = "failed to send message: %v"
 	OAIFailedResponseErr = "response failed: Code: %v, Message: %v"
+	OAIMaxToolIterationsErr = "stopped after %d rounds of function calls in one turn, raise -max-tool-iterations to allow more"
 	OAIErrorResponseErr = "response error: Code: %v, Message: %v"
 	OAILoadFunctionsErr = "failed to load custom functions: %v"
-	OAIUnexpectedFunctionResultType = "unexpected function result type: %v"
+	OAIFunctionResultOutputErr = "failed to serialize function result: %v"
+	OAIFunctionFailedCode = "tool_failed"
+	OAIFunctionTimeoutCode = "tool_timeout"
+	OAIFunctionCancelledCode = "tool_cancelled"
+	OAIFunctionDeniedCode = "tool_denied"
+	OAISaveSessionErr = "failed to save chat session: %v"
+	OAIRecordUsageErr = "failed to record usage: %v"
+	OAIQueuedPromptsDroppedErr = "%v (dropped %d queued prompts)"
+	OAIResumeSessionErr = "failed to resume chat session: %v"
+	OAIResumeWhileStreamingErr = "cannot resume a session while a response is in progress"
+	OAIReconnectErr = "failed to reconnect to OpenAI: %v"
+	OAIClientEventErr = "%s rejected: Code: %v, Message: %v"
+	OAIUpdateWhileStreamingErr = "cannot change the session while a response is in progress"
 )
 
 const (
@@ -48,31 +76,33 @@ const (
 	OAIDisconnectingMsg = "Disconnecting from OpenAI"
 	OAIDisconnectedMsg = "Disconnected from OpenAI"
 	OAISessionCreatedMsg = "Session created"
-	OAIMessageStreamInProgressMsg = "Message stream in progress"
-	OAIFunctionCallDeltaMsg = "- Calling your custom function: %s with args: %s -\n"
+	OAIMessageQueuedMsg = "Message stream in progress, message queued (%d)"
 )
 
 const (
 	// OpenAI general constants
 	OAIInputText = "input_text"
+	OAIOutputText = "output_text"
 	OAIResultText = "result"
 	OAIConversationItemRole = "user"
 	OAIConversationItemType = "message"
 	OAIFunctionFieldName = "name"
 	OAIFunctionCallResultText = "function_call_output"
+	OAIFunctionCallItemType = "function_call"
+	OAIToolChoiceFunctionType = "function"
+	OAIClientEventIDFormat = "evt_client_%d"
+	OAISettingUnsetText = "default"
+	OAISessionSettingsText = "model=%s, temperature=%s, max_output_tokens=%s, tool_choice=%s"
 )
 const (
 	// OpenAI log messages
 	OAISessionCreatedWithIDMsg = "Session created with ID: %s"
-	OAISessionUpdatedMsg = "Session updated."
+	OAISessionUpdatedMsg = "Session updated: %s"
 	OAIResponseCreatedWithIDMsg = "Response created with ID: %s"
 	OAIExecutingFunctionWithArgsMsg = "Executing function: %s with args: %s"
-)
-
-const (
-	// OpenAI function call specific instructions
-	OAIFunctionCallInstructions = "A function named '%s' was called with arguments %s. The function returned: %v. " +
-		"Write a clear reply in natural language, make sure you mention that you have called a custom function " +
-		"and that writes the original problem and incorporates the function result directly as if it was your own. " +
-		"Never recompute or override the function output, always treat it as ground truth."
+	OAIConversationItemTrackedMsg = "Tracked %s conversation item %s from %s"
+	OAIResponseCancelledMsg = "Response %s cancelled by user"
+	OAIFunctionCallsCancelledMsg = "Function calls of response %s cancelled by user"
+	OAIResponseDoneWithStatusMsg = "Response %s done with status: %s"
+	OAISessionResumedMsg = "Resumed session %s with %d turns"
 )
@@ -0,0 +1,96 @@
+package openai
+
+func NewConversation() *Conversation {
+	// Create new conversation model
+	return &Conversation{
+		order:     []string{},
+		items:     make(map[string]OAIConversationItem),
+		truncated: make(map[string]string),
+	}
+}
+
+func (conv *Conversation) UpsertItem(previousItemID string, item OAIConversationItem) {
+	// Add a new item after its previous item, or update an already tracked one in place
+	conv.mu.Lock()
+	defer conv.mu.Unlock()
+
+	if text, isTruncated := conv.truncated[item.ID]; isTruncated {
+		item = truncateItemText(item, text)
+	}
+
+	if _, exists := conv.items[item.ID]; exists {
+		conv.items[item.ID] = item
+		return
+	}
+
+	conv.items[item.ID] = item
+	conv.order = insertAfter(conv.order, previousItemID, item.ID)
+}
+
+func (conv *Conversation) TruncateItem(itemID string, text string) {
+	// Truncate an item to the text that was actually shown, also for later updates of it
+	conv.mu.Lock()
+	defer conv.mu.Unlock()
+
+	conv.truncated[itemID] = text
+	if item, exists := conv.items[itemID]; exists {
+		conv.items[itemID] = truncateItemText(item, text)
+	}
+}
+
+func (conv *Conversation) Reset() {
+	// Drop every tracked item, used when a new realtime session starts
+	conv.mu.Lock()
+	defer conv.mu.Unlock()
+
+	conv.order = []string{}
+	conv.items = make(map[string]OAIConversationItem)
+	conv.truncated = make(map[string]string)
+}
+
+func (conv *Conversation) GetItem(itemID string) (OAIConversationItem, bool) {
+	// Return a tracked item by its ID
+	conv.mu.RLock()
+	defer conv.mu.RUnlock()
+	item, exists := conv.items[itemID]
+	return item, exists
+}
+
+func (conv *Conversation) GetItems() []OAIConversationItem {
+	// Return all tracked items in conversation order
+	conv.mu.RLock()
+	defer conv.mu.RUnlock()
+
+	items := make([]OAIConversationItem, 0, len(conv.order))
+	for _, itemID := range conv.order {
+		items = append(items, conv.items[itemID])
+	}
+	return items
+}
+
+func insertAfter(order []string, previousItemID string, itemID string) []string {
+	// Insert item ID right after the previous item ID, appending when it is unknown
+	if previousItemID != "" {
+		for i, id := range order {
+			if id == previousItemID {
+				order = append(order, "")
+				copy(order[i+2:], order[i+1:])
+				order[i+1] = itemID
+				return order
+			}
+		}
+	}
+	return append(order, itemID)
+}
+
+func truncateItemText(item OAIConversationItem, text string) OAIConversationItem {
+	// Replace the item text content with the truncated text
+	content := make([]OAIConversationItemContent, 0, len(item.Content))
+	for _, part := range item.Content {
+		part.Text = text
+		content = append(content, part)
+		text = ""
+	}
+	item.Content = content
+	return item
+}
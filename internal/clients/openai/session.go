@@ -0,0 +1,120 @@
+package openai
+
+import (
+	"RTGPTGoCLI/internal/clients"
+	"RTGPTGoCLI/internal/common"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"RTGPTGoCLI/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+func (oaic *OpenAIClient) UpdateSession(ctx context.Context, update OAISessionUpdate) *errorhandler.AppError {
+	// Change session settings mid-session, sending only the changed fields with session.update
+	oaic.mu.Lock()
+	sessionConfig := OAISessionConfigMetadata{Type: OAISessionTypeRealtimeText}
+	if update.Instructions != nil {
+		oaic.sessionSettings.Instructions = *update.Instructions
+		sessionConfig.Instructions = *update.Instructions
+	}
+	if update.Temperature != nil {
+		oaic.sessionSettings.Temperature = update.Temperature
+		sessionConfig.Temperature = update.Temperature
+	}
+	if update.MaxOutputTokens != nil {
+		oaic.sessionSettings.MaxOutputTokens = update.MaxOutputTokens
+		sessionConfig.MaxOutputTokens = update.MaxOutputTokens
+	}
+	if update.ToolChoice != nil {
+		oaic.sessionSettings.ToolChoice = update.ToolChoice
+		sessionConfig.ToolChoice = update.ToolChoice
+	}
+	oaic.pendingSettingsConfirmation = true
+	oaic.mu.Unlock()
+
+	sessionUpdatePayload := OAISessionConfigPayload{
+		Type:    OAISessionUpdateEventType,
+		EventID: oaic.newClientEventID(OAISessionUpdateEventType),
+		Session: sessionConfig,
+	}
+	return oaic.sendToWebSocket(ctx, sessionUpdatePayload)
+}
+
+func (oaic *OpenAIClient) SetModel(ctx context.Context, model string) *errorhandler.AppError {
+	// Switch model by reconnecting with it, replaying the conversation into the new session
+	if oaic.getIsStreaming() {
+		return errorhandler.NewAppError(errorhandler.WarningLevel, OAIUpdateWhileStreamingErr, errors.New(OAIUpdateWhileStreamingErr))
+	}
+
+	oaic.mu.Lock()
+	oaic.sessionSettings.Model = model
+	oaic.pendingSettingsConfirmation = true
+	oaic.mu.Unlock()
+
+	session := oaic.GetSession()
+	session.SetModel(model)
+	oaic.wsc.SetModel(model)
+
+	if appErr := oaic.reconnect(ctx); appErr != nil {
+		return appErr
+	}
+	return oaic.replayItems(ctx, session.GetItems())
+}
+
+func (oaic *OpenAIClient) GetSessionSettings() OAISessionSettings {
+	// Return the session settings as last confirmed by the server
+	oaic.mu.RLock()
+	defer oaic.mu.RUnlock()
+	return oaic.confirmedSettings
+}
+
+func (oaic *OpenAIClient) getSessionSettings() OAISessionSettings {
+	// Return the session settings requested by the user
+	oaic.mu.RLock()
+	defer oaic.mu.RUnlock()
+	return oaic.sessionSettings
+}
+
+func (oaic *OpenAIClient) handleSessionUpdated(msg []byte) {
+	// Handle session updated event, keeping the settings confirmed by the server
+	var updated OAISessionUpdatedEventPayload
+	if err := json.Unmarshal(msg, &updated); err != nil {
+		oaic.reportError(common.NewErrJsonUnmarshalAppError(err))
+		return
+	}
+
+	oaic.mu.Lock()
+	oaic.confirmedSettings = updated.Session
+	if oaic.confirmedSettings.Model == "" {
+		oaic.confirmedSettings.Model = oaic.sessionSettings.Model
+	}
+	confirmed := oaic.confirmedSettings
+	notify := oaic.pendingSettingsConfirmation
+	oaic.pendingSettingsConfirmation = false
+	oaic.mu.Unlock()
+
+	logger.Debug(fmt.Sprintf(OAISessionUpdatedMsg, describeSessionSettings(confirmed)))
+	oaic.events.Publish(clients.SessionUpdatedEvent{Settings: describeSessionSettings(confirmed), Requested: notify})
+}
+
+func describeSessionSettings(settings OAISessionSettings) string {
+	// Describe session settings in a single line
+	temperature := OAISettingUnsetText
+	if settings.Temperature != nil {
+		temperature = fmt.Sprintf("%v", *settings.Temperature)
+	}
+
+	maxOutputTokens := OAISettingUnsetText
+	if settings.MaxOutputTokens != nil {
+		maxOutputTokens = fmt.Sprintf("%v", settings.MaxOutputTokens)
+	}
+
+	toolChoice := fmt.Sprintf("%v", settings.ToolChoice)
+	if choice, ok := settings.ToolChoice.(map[string]interface{}); ok {
+		toolChoice = fmt.Sprintf("%v", choice[OAIFunctionFieldName])
+	}
+
+	return fmt.Sprintf(OAISessionSettingsText, settings.Model, temperature, maxOutputTokens, toolChoice)
+}
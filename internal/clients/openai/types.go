@@ -1,10 +1,14 @@
 package openai
 
 import (
+	"RTGPTGoCLI/internal/budget"
 	"RTGPTGoCLI/internal/clients"
 	"RTGPTGoCLI/internal/config"
 	"RTGPTGoCLI/internal/functions/handler"
+	"RTGPTGoCLI/internal/sessions"
 	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"strings"
 	"sync"
 )
 
@@ -12,6 +16,22 @@ type OpenAIClientInterface interface {
 	// OpenAIClient interface
 	clients.ServiceClientConnection
 	GetAvailableFunctions() []string
+	GetToolPolicy(name string) string
+	SetApprover(approver handler.Approver)
+	GetConversation() []OAIConversationItem
+	CancelResponse(ctx context.Context) *errorhandler.AppError
+	IsStreaming() bool
+	GetQueuedMessages() []string
+	DropQueuedMessage(index int) bool
+	ClearQueue() int
+	GetSession() *sessions.Session
+	ResumeSession(ctx context.Context, sessionID string) *errorhandler.AppError
+	UpdateSession(ctx context.Context, update OAISessionUpdate) *errorhandler.AppError
+	SetModel(ctx context.Context, model string) *errorhandler.AppError
+	GetSessionSettings() OAISessionSettings
+	GetBudgetStatuses() ([]budget.Status, error)
+	OverrideBudget()
+	IsBudgetOverridden() bool
 }
 
 type OpenAIClient struct {
@@ -22,17 +42,40 @@ type OpenAIClient struct {
 	cleanUpOnce sync.Once
 
 	functionHandler *handler.FunctionHandler
+	conversation *Conversation
+	sessionStore *sessions.Store
+	session      *sessions.Session
+	budget       *budget.Tracker
 	sessionID   string
 	responseID  string
 	isStreaming bool
 
-	messageChannel chan clients.MessageEvent
+	toolIterations      int
+	cancelTools         context.CancelFunc
+	functionCalls       sync.WaitGroup
+	messageLoop         sync.WaitGroup
+	closing             chan struct{}
+	inputQueue          []string
+
+	sessionSettings   OAISessionSettings
+	confirmedSettings OAISessionSettings
+	pendingSettingsConfirmation bool
+	clientEventCount  int
+	clientEventTypes  map[string]string
+
+	cancelledResponseID string
+	streamingItemID     string
+	streamedText        strings.Builder
+	streamingCalls      map[string]*clients.FunctionCallEvent
+
+	events         *clients.EventBus
 	errorChannel   chan errorhandler.AppError	
 }
 
 type OAISessionConfigPayload struct {
 	// OpenAI session config struct
 	Type    string             `json:"type"`
+	EventID string             `json:"event_id,omitempty"`
 	Session OAISessionConfigMetadata `json:"session"`
 }
 
@@ -42,7 +85,38 @@ type OAISessionConfigMetadata struct {
 	OutputModalities []string `json:"output_modalities,omitempty"`
 	Instructions     string   `json:"instructions,omitempty"`
 	Tools	            []interface{} `json:"tools,omitempty"`
-	ToolChoice       string   `json:"tool_choice,omitempty"`
+	ToolChoice       interface{} `json:"tool_choice,omitempty"`
+	Temperature      *float64    `json:"temperature,omitempty"`
+	MaxOutputTokens  interface{} `json:"max_output_tokens,omitempty"`
+}
+
+type OAISessionSettings struct {
+	// OpenAI session settings, as requested by the user or as confirmed by the server
+	Model           string      `json:"model,omitempty"`
+	Instructions    string      `json:"instructions,omitempty"`
+	Temperature     *float64    `json:"temperature,omitempty"`
+	MaxOutputTokens interface{} `json:"max_output_tokens,omitempty"`
+	ToolChoice      interface{} `json:"tool_choice,omitempty"`
+}
+
+type OAISessionUpdate struct {
+	// OpenAI session settings change, nil fields are left untouched
+	Instructions    *string
+	Temperature     *float64
+	MaxOutputTokens interface{}
+	ToolChoice      interface{}
+}
+
+type OAISessionUpdatedEventPayload struct {
+	// OpenAI session updated event payload
+	Type    string             `json:"type"`
+	Session OAISessionSettings `json:"session"`
+}
+
+type OAIToolChoiceFunction struct {
+	// OpenAI tool choice forcing a specific function
+	Type string `json:"type"`
+	Name string `json:"name"`
 }
 
 type OAIConversationPayload struct {
@@ -76,6 +150,43 @@ type OAIResponseMetadata struct {
 	Instructions string `json:"instructions,omitempty"`
 }
 
+type OAIResponseDonePayload struct {
+	// OpenAI response done event payload
+	Type     string                  `json:"type"`
+	Response OAIResponseDoneMetadata `json:"response"`
+}
+
+type OAIResponseDoneMetadata struct {
+	// OpenAI response done metadata struct
+	ID            string                   `json:"id"`
+	Status        string                   `json:"status"`
+	StatusDetails OAIResponseStatusDetails `json:"status_details"`
+	Output        []OAIConversationItem    `json:"output"`
+	Usage         *OAIResponseUsage        `json:"usage,omitempty"`
+}
+
+type OAIResponseUsage struct {
+	// OpenAI response usage struct
+	TotalTokens       int                  `json:"total_tokens"`
+	InputTokens       int                  `json:"input_tokens"`
+	OutputTokens      int                  `json:"output_tokens"`
+	InputTokenDetails OAIInputTokenDetails `json:"input_token_details"`
+}
+
+type OAIInputTokenDetails struct {
+	// OpenAI input token details struct
+	CachedTokens int `json:"cached_tokens"`
+	TextTokens   int `json:"text_tokens"`
+	AudioTokens  int `json:"audio_tokens"`
+}
+
+type OAIResponseStatusDetails struct {
+	// OpenAI response status details struct
+	Type   string           `json:"type"`
+	Reason string           `json:"reason"`
+	Error  OAIErrorMetadata `json:"error"`
+}
+
 type OAIStreamingEvent struct {
 	// OpenAI streaming event struct
 	Type string `json:"type"`
@@ -104,9 +215,33 @@ type OAIResponseCreatedMetadata struct {
 	Status string `json:"status"`
 }
 
+type OAIResponseEventMetadata struct {
+	// OpenAI response scoped event metadata struct
+	Type       string `json:"type"`
+	ResponseID string `json:"response_id"`
+	Text       string `json:"text,omitempty"`
+}
+
+type OAIResponseCancelPayload struct {
+	// OpenAI response cancel payload
+	Type       string `json:"type"`
+	EventID    string `json:"event_id,omitempty"`
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+type OAIConversationItemTruncatePayload struct {
+	// OpenAI conversation item truncate payload
+	Type         string `json:"type"`
+	EventID      string `json:"event_id,omitempty"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	AudioEndMs   int    `json:"audio_end_ms"`
+}
+
 type OAIResponseOutPutTextDeltaPayload struct {
 	// OpenAI response output text delta payload
 	Type      string `json:"type"`
+	ResponseID string `json:"response_id"`
 	ItemId    string `json:"item_id"`
 	Delta     string `json:"delta"`
 	SeqNumber int    `json:"sequence_number"`
@@ -141,16 +276,33 @@ type OAIErrorMetadata struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Param   string `json:"param,omitempty"`
+	EventID string `json:"event_id,omitempty"`
 }
 
 type OAIFunctionCallDonePayload struct {
 	// Function call done payload
 	Type      string `json:"type"`
+	ResponseID string `json:"response_id"`
 	CallID    string `json:"call_id"`
 	Name      string `json:"name"`
 	Arguments string `json:"arguments"`
 }
 
+type OAIFunctionCallDeltaPayload struct {
+	// Function call arguments delta payload
+	Type       string `json:"type"`
+	ResponseID string `json:"response_id"`
+	ItemID     string `json:"item_id"`
+	CallID     string `json:"call_id"`
+	Delta      string `json:"delta"`
+}
+
+type OAIRateLimitsUpdatedPayload struct {
+	// OpenAI rate limits updated event payload
+	Type       string              `json:"type"`
+	RateLimits []clients.RateLimit `json:"rate_limits"`
+}
+
 type OAIFunctionCallResultPayload struct {
 	// Function call result payload
 	Type      string `json:"type"`
@@ -164,13 +316,43 @@ type OAIFunctionResultItemMetadata struct {
 	Output interface{} `json:"output"`
 }
 
-type OAIFunctionResultContinuePayload struct {
-	// Function call result continue payload
-	Type string `json:"type"`
-	Response FunctionContinueResponseMetadata `json:"response"`
+type Conversation struct {
+	// Conversation struct, tracking every item of the session by its ID in order
+	mu        sync.RWMutex
+	order     []string
+	items     map[string]OAIConversationItem
+	truncated map[string]string
+}
+
+type OAIConversationItem struct {
+	// OpenAI conversation item struct (message, function_call or function_call_output)
+	ID        string                       `json:"id"`
+	Type      string                       `json:"type"`
+	Role      string                       `json:"role,omitempty"`
+	Status    string                       `json:"status,omitempty"`
+	Content   []OAIConversationItemContent `json:"content,omitempty"`
+	CallID    string                       `json:"call_id,omitempty"`
+	Name      string                       `json:"name,omitempty"`
+	Arguments string                       `json:"arguments,omitempty"`
+	Output    string                       `json:"output,omitempty"`
 }
 
-type FunctionContinueResponseMetadata struct {
-	// Function call result continue response metadata struct
-	Instructions string `json:"instructions"`
+type OAIConversationItemCreatePayload struct {
+	// OpenAI conversation item create payload for any item type
+	Type string              `json:"type"`
+	Item OAIConversationItem `json:"item"`
+}
+
+type OAIConversationItemDonePayload struct {
+	// OpenAI conversation.item.done, response.output_item.added and response.output_item.done payload
+	Type           string              `json:"type"`
+	PreviousItemID string              `json:"previous_item_id,omitempty"`
+	ResponseID     string              `json:"response_id,omitempty"`
+	Item           OAIConversationItem `json:"item"`
+}
+
+type OAIFunctionErrorOutput struct {
+	// Function call output of a failed call
+	Error   string `json:"error"`
+	Message string `json:"message"`
 }
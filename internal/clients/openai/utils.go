@@ -0,0 +1,116 @@
+package openai
+
+import (
+	"RTGPTGoCLI/internal/clients"
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/internal/functions/handler"
+	"RTGPTGoCLI/internal/sessions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+func (usage *OAIResponseUsage) toUsageEvent() *clients.UsageEvent {
+	// Convert OpenAI usage block to a client usage event
+	if usage == nil {
+		return nil
+	}
+	return &clients.UsageEvent{
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		CachedTokens: usage.InputTokenDetails.CachedTokens,
+		TotalTokens:  usage.TotalTokens,
+	}
+}
+
+func (item OAIConversationItem) toSessionItem() sessions.Item {
+	// Convert conversation item to a session item, flattening its text content
+	texts := []string{}
+	for _, part := range item.Content {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+
+	return sessions.Item{
+		ID:        item.ID,
+		Type:      item.Type,
+		Role:      item.Role,
+		Text:      strings.Join(texts, ""),
+		CallID:    item.CallID,
+		Name:      item.Name,
+		Arguments: item.Arguments,
+		Output:    item.Output,
+	}
+}
+
+func fromSessionItem(item sessions.Item) OAIConversationItem {
+	// Convert session item back to a conversation item for replay
+	conversationItem := OAIConversationItem{
+		ID:        item.ID,
+		Type:      item.Type,
+		Role:      item.Role,
+		CallID:    item.CallID,
+		Name:      item.Name,
+		Arguments: item.Arguments,
+		Output:    item.Output,
+	}
+
+	if item.Type == OAIConversationItemType {
+		contentType := OAIInputText
+		if item.Role != OAIConversationItemRole {
+			contentType = OAIOutputText
+		}
+		conversationItem.Content = []OAIConversationItemContent{{Type: contentType, Text: item.Text}}
+	}
+	return conversationItem
+}
+
+func sessionInstructions(cfg *config.Config) string {
+	// Return the configured session instructions, or the default ones
+	if cfg.Instructions != "" {
+		return cfg.Instructions
+	}
+	return OAISessionInstructionsText
+}
+
+func functionErrorOutput(appErr *errorhandler.AppError) string {
+	// Convert a failed call to the structured output sent to the model
+	var argsErr *functions.ArgumentsError
+	if errors.As(appErr.Error, &argsErr) {
+		return argsErr.Output()
+	}
+
+	code := OAIFunctionFailedCode
+	switch {
+	case errors.Is(appErr.Error, context.DeadlineExceeded):
+		code = OAIFunctionTimeoutCode
+	case errors.Is(appErr.Error, context.Canceled):
+		code = OAIFunctionCancelledCode
+	case errors.Is(appErr.Error, handler.ErrFunctionDenied):
+		code = OAIFunctionDeniedCode
+	}
+
+	output, err := json.Marshal(OAIFunctionErrorOutput{
+		Error:   code,
+		Message: appErr.Message,
+	})
+	if err != nil {
+		return appErr.Message
+	}
+	return string(output)
+}
+
+func (response OAIResponseDoneMetadata) functionCalls() []OAIConversationItem {
+	// Return the function calls of a response output
+	calls := []OAIConversationItem{}
+	for _, item := range response.Output {
+		if item.Type == OAIFunctionCallItemType {
+			calls = append(calls, item)
+		}
+	}
+	return calls
+}
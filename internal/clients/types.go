@@ -3,13 +3,143 @@ package clients
 import (
 	"RTGPTGoCLI/pkg/errorhandler"
 	"context"
+	"sync"
 )
 
-type MessageEvent struct {
-	// Message event struct
-	Type string
+type Event interface {
+	// Client event union, every event published on the message channel implements it
+	EventType() string
+}
+
+type TextDeltaEvent struct {
+	// Streamed chunk of assistant text
+	ResponseID string
+	ItemID     string
+	Delta      string
+}
+
+type TextDoneEvent struct {
+	// Complete assistant text of a response
+	ResponseID string
+	Text       string
+}
+
+type ToolCallArgsDeltaEvent struct {
+	// Streamed chunk of function call arguments, the call carries the arguments generated so far
+	ResponseID string
+	Delta      string
+	Call       FunctionCallEvent
+}
+
+type ToolCallStartedEvent struct {
+	// Function call approved and about to run
+	ResponseID string
+	Call       FunctionCallEvent
+}
+
+type ToolCallCompletedEvent struct {
+	// Function call that returned a result
+	ResponseID string
+	Call       FunctionCallEvent
+}
+
+type ToolCallFailedEvent struct {
+	// Function call that was rejected, denied or failed while running
+	ResponseID string
+	Call       FunctionCallEvent
+}
+
+type ResponseStartedEvent struct {
+	// Response created by the server
+	ResponseID string
+}
+
+type ResponseDoneEvent struct {
+	// Response done on the server, with its status and token usage
+	ResponseID string
+	Status     string
+	Usage      *UsageEvent
+}
+
+type ResponseCancelledEvent struct {
+	// Response or running function calls cancelled by the user
+	ResponseID string
+}
+
+type TurnDoneEvent struct {
+	// Turn done, after the last response of the turn and its function calls
+	ResponseID string
+}
+
+type QueuedMessageSentEvent struct {
+	// Queued prompt sent once the previous turn was done
 	Text string
-	Done bool
+}
+
+type SessionUpdatedEvent struct {
+	// Session settings confirmed by the server, requested when the user asked for the update
+	Settings  string
+	Requested bool
+}
+
+type SessionUpdateRejectedEvent struct {
+	// Session update rejected by the server
+	Reason string
+}
+
+type BudgetWarningEvent struct {
+	// Budget that reached the warning threshold or its limit
+	Message string
+	Reached bool
+}
+
+type RateLimitsEvent struct {
+	// Rate limits reported by the server
+	Limits []RateLimit
+}
+
+type RateLimit struct {
+	// Rate limit of a single resource, e.g. requests or tokens
+	Name         string  `json:"name"`
+	Limit        int     `json:"limit"`
+	Remaining    int     `json:"remaining"`
+	ResetSeconds float64 `json:"reset_seconds"`
+}
+
+type ConnectionStateEvent struct {
+	// Connection state change, with the error that caused it when the connection was lost
+	State string
+	Error string
+}
+
+type EventBus struct {
+	// Fan-out bus, delivering every published event to each subscriber in order
+	mu          sync.RWMutex
+	subscribers []chan Event
+	buffer      int
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+type FunctionCallEvent struct {
+	// Function call event struct, carrying the call arguments (partial while generating) and its result or error
+	CallID     string      `json:"call_id"`
+	Name       string      `json:"name"`
+	Arguments  string      `json:"arguments"`
+	Status     string      `json:"status,omitempty"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"duration_ms,omitempty"`
+}
+
+type UsageEvent struct {
+	// Token usage of a single response, with its cost when the model has a price
+	InputTokens  int      `json:"input_tokens"`
+	OutputTokens int      `json:"output_tokens"`
+	CachedTokens int      `json:"cached_tokens"`
+	TotalTokens  int      `json:"total_tokens"`
+	Cost         *float64 `json:"cost,omitempty"`
+	Currency     string   `json:"currency,omitempty"`
 }
 
 type ClientConnection interface {
@@ -23,6 +153,8 @@ type ClientConnection interface {
 type WebClientConnection interface {
 	// Web client connection interface
 	ClientConnection
+	Reconnect(ctx context.Context) error
+	SetModel(model string)
 	SendMessage(ctx context.Context, message []byte) error
 	GetMessageChannel() <-chan []byte
 }
@@ -30,6 +162,6 @@ type WebClientConnection interface {
 type ServiceClientConnection interface {
 	// Service client connection interface
 	ClientConnection
-	GetMessageChannel() <-chan MessageEvent
+	GetMessageChannel() <-chan Event
 	SendMessage(ctx context.Context, message string) *errorhandler.AppError
 }
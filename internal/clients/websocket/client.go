@@ -55,22 +55,23 @@ func (wsc *WebSocketClient) Disconnect() error {
         if !wsc.IsConnected() {
             return
         }
-		
-        if wsc.cancel != nil {
-            wsc.cancel()
-        }
 
         wsc.mu.Lock()
         defer wsc.mu.Unlock()
 
+        if wsc.cancel != nil {
+            wsc.cancel()
+        }
+
         close(wsc.sendChannel)
         close(wsc.messageChannel)
         close(wsc.errorChannel)
 
         
         if wsc.connection != nil {
-			// Close WebSocket connection
-            if err := wsc.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+			// Close WebSocket connection, WriteControl is safe to call while the write routine writes
+            closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+            if err := wsc.connection.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(WSCloseTimeout)); err != nil {
                 disconnectErr = fmt.Errorf(WSCloseErr, err)
             }
 
@@ -79,7 +80,7 @@ func (wsc *WebSocketClient) Disconnect() error {
             }
         }
         
-        wsc.setConnected(false)
+        wsc.connected = false
     })
     
     return disconnectErr
@@ -118,29 +119,68 @@ func (wsc *WebSocketClient) GetMessageChannel() <-chan []byte {
 
 func (wsc *WebSocketClient) connectOrRetry(ctx context.Context) error {
 	// Connect (or retry connecting) to the WebSocket server
-	if wsc.cancel != nil {
-		wsc.cancel()
+	if wsc.closeConnection() {
 		time.Sleep(time.Duration(wsc.config.Timeout) * time.Second)
 	}
 
-	connectionContext, cancel := context.WithCancel(ctx)
-	wsc.cancel = cancel
+	return wsc.dial(ctx)
+}
+
+func (wsc *WebSocketClient) Reconnect(ctx context.Context) error {
+	// Replace the connection with a fresh one right away, keeping the client channels open
+	wsc.closeConnection()
+
+	if err := wsc.dial(ctx); err != nil {
+		return fmt.Errorf(WSConnectionErr, err)
+	}
+	return nil
+}
+
+func (wsc *WebSocketClient) SetModel(model string) {
+	// Set the model used by the next (re)connection
+	wsc.mu.Lock()
+	defer wsc.mu.Unlock()
+	wsc.url = fmt.Sprintf(WSUrlBuild, wsc.config.BaseURL, model)
+}
+
+func (wsc *WebSocketClient) closeConnection() bool {
+	// Stop the routines of the current connection and close it, returning if there was one
+	wsc.mu.Lock()
+	defer wsc.mu.Unlock()
 
+	hadConnection := wsc.cancel != nil
+	if wsc.cancel != nil {
+		wsc.cancel()
+	}
 	if wsc.connection != nil {
 		wsc.connection.Close()
 	}
+	wsc.connected = false
+	return hadConnection
+}
 
-	connection, _, err := websocket.DefaultDialer.Dial(wsc.url, wsc.headers)
+func (wsc *WebSocketClient) dial(ctx context.Context) error {
+	// Dial the WebSocket server and start the read and write routines
+	connectionContext, cancel := context.WithCancel(ctx)
+
+	wsc.mu.Lock()
+	wsc.cancel = cancel
+	url := wsc.url
+	wsc.mu.Unlock()
+
+	connection, _, err := websocket.DefaultDialer.Dial(url, wsc.headers)
 	if err != nil {
 		return err
 	}
 
+	wsc.mu.Lock()
 	wsc.connection = connection
-	wsc.setConnected(true)
+	wsc.connected = true
+	wsc.mu.Unlock()
 	logger.Debug(WSConnectedMsg)
 
-	go wsc.readRoutine(connectionContext)
-	go wsc.writeRoutine(connectionContext)
+	go wsc.readRoutine(connectionContext, connection)
+	go wsc.writeRoutine(connectionContext, connection)
 
 	return nil
 }
@@ -152,8 +192,8 @@ func (wsc *WebSocketClient) setConnected(state bool) {
 	wsc.connected = state
 }
 
-func (wsc *WebSocketClient) readRoutine(ctx context.Context) {
-	// Read messages from websocket in go routine
+func (wsc *WebSocketClient) readRoutine(ctx context.Context, connection *websocket.Conn) {
+	// Read messages from the connection in go routine
 	defer func() {
 		if r := recover(); r != nil {
 			wsc.setConnected(false)
@@ -170,8 +210,13 @@ func (wsc *WebSocketClient) readRoutine(ctx context.Context) {
 				return
 			}
 
-			_, response, err := wsc.connection.ReadMessage()
+			_, response, err := connection.ReadMessage()
 			if err != nil {
+				if ctx.Err() != nil {
+					// Connection was replaced or closed on purpose
+					return
+				}
+
 				wsc.setConnected(false)
 				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
 					wsc.errorChannel <- *errorhandler.NewAppError(errorhandler.WarningLevel, WSConnectionIsClosedErr, errors.New(WSConnectionIsClosedErr))
@@ -195,8 +240,8 @@ func (wsc *WebSocketClient) readRoutine(ctx context.Context) {
 	}
 }
 
-func (wsc *WebSocketClient) writeRoutine(ctx context.Context) {
-	// Write messages to websocket in go routine
+func (wsc *WebSocketClient) writeRoutine(ctx context.Context, connection *websocket.Conn) {
+	// Write messages to the connection in go routine
 	defer func() {
 		if r := recover(); r != nil {
 			wsc.setConnected(false)
@@ -208,6 +253,15 @@ func (wsc *WebSocketClient) writeRoutine(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case prompt := <-wsc.sendChannel:
+			if ctx.Err() != nil {
+				// Connection is being replaced, hand the message over to the new write routine
+				select {
+				case wsc.sendChannel <- prompt:
+				default:
+				}
+				return
+			}
+
 			if !wsc.IsConnected() {
 				wsc.errorChannel <- errorhandler.AppError{
 					Level: errorhandler.WarningLevel,
@@ -217,7 +271,7 @@ func (wsc *WebSocketClient) writeRoutine(ctx context.Context) {
 				continue
 			}
 
-			err := wsc.connection.WriteMessage(websocket.TextMessage, prompt)
+			err := connection.WriteMessage(websocket.TextMessage, prompt)
 			if err != nil {
 				wsc.errorChannel <- errorhandler.AppError{
 					Level: errorhandler.WarningLevel,
@@ -1,10 +1,13 @@
 package websocket
 
+import "time"
+
 const (
 	// Websocket client constants
 	WSAuthHeader   = "Authorization"
 	WSBearerPrefix = "Bearer "
 	WSUrlBuild     = "wss://%s?model=%s"
+	WSCloseTimeout = time.Second
 )
 
 const (
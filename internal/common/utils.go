@@ -1,14 +1,15 @@
 package common
 
 import (
+	"RTGPTGoCLI/pkg/redact"
 	"encoding/json"
 	"fmt"
 )
 
 func PrintJSON(data []byte) {
-	// Print JSON data for debugging
+	// Print JSON data for debugging, with secrets masked
 	var message interface{}
-	json.Unmarshal(data, &message)
+	json.Unmarshal(redact.JSON(data), &message)
 	prettyJSON, _ := json.MarshalIndent(message, "", "  ")
 	fmt.Println("JSON: ", string(prettyJSON))
 }
@@ -2,10 +2,13 @@ package config
 
 import (
 	"RTGPTGoCLI/pkg/logger"
-	"encoding/json"
+	"RTGPTGoCLI/pkg/redact"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,12 +17,14 @@ import (
 
 func SetUp() (*Config, error) {
 	// Initialize config
-	cfg := &Config{}
+	cfg := &Config{sources: make(map[FlagType]string)}
 
 	if err := cfg.loadVars(); err != nil {
 		return nil, err
 	}
 
+	redact.AddSecret(cfg.APIKey)
+
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
@@ -28,21 +33,22 @@ func SetUp() (*Config, error) {
 }
 
 func (cfg *Config) loadVars() error {
-	// Load config from environment variables and flags
-	cfg.loadFromEnv()
-	cfg.loadFromFlags()
-
-	return nil
-}
-
-func (cfg *Config) loadFromEnv() {
-	// Load config from environment variables
+	// Load config layers, by precedence: defaults < file < profile < env < flags
 	cfg.setDefaults()
+	cfg.loadEnvFile()
+	setFlags := cfg.loadFromFlags()
+
+	if err := cfg.loadFromFile(setFlags); err != nil {
+		return err
+	}
 	cfg.loadEnvVars()
+
+	return cfg.applyFlags(setFlags)
 }
 
 func (cfg *Config) setDefaults() {
 	// Set default values
+	cfg.setSource(DefaultSource, ApiKeyFlag, BaseURLFlag, TimeoutFlag, ModelFlag, DebugFlag, RetriesFlag, ChannelBufferFlag, OutputFlag, DataDirFlag, InstructionsFlag, ToolsFlag, ToolsDirFlag, ToolTimeoutFlag, ToolParallelismFlag, MaxToolIterationsFlag, MCPServersKey, CustomToolsKey, ToolTimeoutsKey, ToolPolicyFlag, ToolPoliciesKey, PricesKey, CurrencyKey, BudgetsKey, BudgetWarningFlag, ColorFlag)
 	cfg.APIKey = DefaultAPIKey
 	cfg.BaseURL = DefaultBaseURL
 	cfg.Timeout = DefaultTimeout
@@ -50,37 +56,120 @@ func (cfg *Config) setDefaults() {
 	cfg.Debug = DefaultDebug
 	cfg.Retries = DefaultRetries
 	cfg.ChannelBuffer = DefaultChannelBuffer
+	cfg.Output = DefaultOutput
+	cfg.DataDir = defaultDataDir()
+	cfg.Instructions = DefaultInstructions
+	cfg.Tools = []string{}
+	cfg.ToolsDir = defaultToolsDir()
+	cfg.ToolTimeout = DefaultToolTimeout
+	cfg.ToolParallelism = DefaultToolParallelism
+	cfg.MaxToolIterations = DefaultMaxToolIterations
+	cfg.MCPServers = map[string]MCPServerConfig{}
+	cfg.CustomTools = map[string]CustomToolConfig{}
+	cfg.ToolTimeouts = map[string]int{}
+	cfg.ToolPolicy = DefaultToolPolicy
+	cfg.ToolPolicies = defaultToolPolicies()
+	cfg.Prices = defaultPrices()
+	cfg.Currency = DefaultCurrency
+	cfg.Budgets = BudgetsConfig{}
+	cfg.BudgetWarning = DefaultBudgetWarning
+	cfg.UI.Color = DefaultColor
 }
 
-func (cfg *Config) loadEnvVars() {
-	// Load config from environment variables
+func (cfg *Config) loadEnvFile() {
+	// Load .env file into the environment, without overriding existing variables
 	err := godotenv.Load()
 	if err != nil {
 		logger.Warning(MissingOrErrorLoadingEnvFileErr)
 	}
+}
+
+func (cfg *Config) loadEnvVars() {
+	// Load config from environment variables
 	cfg.setStringEnvVar(ApiKeyFlag, &cfg.APIKey)
 	cfg.setStringEnvVar(BaseURLFlag, &cfg.BaseURL)
 	cfg.setStringEnvVar(ModelFlag, &cfg.Model)
+	cfg.setStringEnvVar(OutputFlag, &cfg.Output)
+	cfg.setStringEnvVar(DataDirFlag, &cfg.DataDir)
+	cfg.setStringEnvVar(InstructionsFlag, &cfg.Instructions)
+	cfg.setStringListEnvVar(ToolsFlag, &cfg.Tools)
+	cfg.setStringEnvVar(ToolsDirFlag, &cfg.ToolsDir)
+	cfg.setStringEnvVar(ToolPolicyFlag, &cfg.ToolPolicy)
 
 	cfg.setIntEnvVar(TimeoutFlag, &cfg.Timeout)
 	cfg.setIntEnvVar(RetriesFlag, &cfg.Retries)
 	cfg.setIntEnvVar(ChannelBufferFlag, &cfg.ChannelBuffer)
+	cfg.setIntEnvVar(ToolTimeoutFlag, &cfg.ToolTimeout)
+	cfg.setIntEnvVar(ToolParallelismFlag, &cfg.ToolParallelism)
+	cfg.setIntEnvVar(MaxToolIterationsFlag, &cfg.MaxToolIterations)
+	cfg.setIntEnvVar(BudgetWarningFlag, &cfg.BudgetWarning)
 
 	cfg.setBoolEnvVar(DebugFlag, &cfg.Debug)
+	cfg.setBoolEnvVar(ColorFlag, &cfg.UI.Color)
 }
 
-func (cfg *Config) loadFromFlags() {
-	// Load config from flags
+func (cfg *Config) loadFromFlags() map[FlagType]string {
+	// Parse flags, returning the explicitly set ones so they are applied last
+	flag.StringVar(&cfg.ConfigPath, string(ConfigFlag), cfg.ConfigPath, ConfigFlagUsageText)
+	flag.StringVar(&cfg.Profile, string(ProfileFlag), cfg.Profile, ProfileFlagUsageText)
+
 	flag.StringVar(&cfg.APIKey, string(ApiKeyFlag), cfg.APIKey, APIKeyFlagUsageText)
 	flag.StringVar(&cfg.BaseURL, string(BaseURLFlag), cfg.BaseURL, BaseURLFlagUsageText)
 	flag.StringVar(&cfg.Model, string(ModelFlag), cfg.Model, ModelFlagUsageText)
+	flag.StringVar(&cfg.Instructions, string(InstructionsFlag), cfg.Instructions, InstructionsFlagUsageText)
+	flag.Var(&stringListValue{values: &cfg.Tools}, string(ToolsFlag), ToolsFlagUsageText)
+	flag.StringVar(&cfg.ToolsDir, string(ToolsDirFlag), cfg.ToolsDir, ToolsDirFlagUsageText)
+	flag.StringVar(&cfg.ToolPolicy, string(ToolPolicyFlag), cfg.ToolPolicy, ToolPolicyFlagUsageText)
+	flag.StringVar(&cfg.Prompt, string(PromptFlag), cfg.Prompt, PromptFlagUsageText)
+	flag.StringVar(&cfg.Output, string(OutputFlag), cfg.Output, OutputFlagUsageText)
+	flag.StringVar(&cfg.DataDir, string(DataDirFlag), cfg.DataDir, DataDirFlagUsageText)
+	flag.StringVar(&cfg.Resume, string(ResumeFlag), cfg.Resume, ResumeFlagUsageText)
 
 	flag.IntVar(&cfg.Timeout, string(TimeoutFlag), cfg.Timeout, TimeoutFlagUsageText)
 	flag.IntVar(&cfg.Retries, string(RetriesFlag), cfg.Retries, RetriesFlagUsageText)
 	flag.IntVar(&cfg.ChannelBuffer, string(ChannelBufferFlag), cfg.ChannelBuffer, ChannelBufferFlagUsageText)
+	flag.IntVar(&cfg.ToolTimeout, string(ToolTimeoutFlag), cfg.ToolTimeout, ToolTimeoutFlagUsageText)
+	flag.IntVar(&cfg.ToolParallelism, string(ToolParallelismFlag), cfg.ToolParallelism, ToolParallelismFlagUsageText)
+	flag.IntVar(&cfg.MaxToolIterations, string(MaxToolIterationsFlag), cfg.MaxToolIterations, MaxToolIterationsFlagUsageText)
+	flag.IntVar(&cfg.BudgetWarning, string(BudgetWarningFlag), cfg.BudgetWarning, BudgetWarningFlagUsageText)
 
 	flag.BoolVar(&cfg.Debug, string(DebugFlag), cfg.Debug, DebugFlagUsageText)
+	flag.BoolVar(&cfg.UI.Color, string(ColorFlag), cfg.UI.Color, ColorFlagUsageText)
+	flag.BoolVar(&cfg.Yes, string(YesFlag), cfg.Yes, YesFlagUsageText)
+	flag.BoolVar(&cfg.DenyUnsafe, string(DenyUnsafeFlag), cfg.DenyUnsafe, DenyUnsafeFlagUsageText)
 	flag.Parse()
+
+	if args := flag.Args(); len(args) > 0 {
+		cfg.Command = args[0]
+		cfg.CommandArgs = args[1:]
+	}
+
+	setFlags := make(map[FlagType]string)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[FlagType(f.Name)] = f.Value.String()
+	})
+	return setFlags
+}
+
+func (cfg *Config) applyFlags(setFlags map[FlagType]string) error {
+	// Apply the explicitly set flags on top of every other layer
+	for name, value := range setFlags {
+		if err := flag.Set(string(name), value); err != nil {
+			return err
+		}
+		cfg.setSource(fmt.Sprintf(FlagSourceText, name), name)
+	}
+	return nil
+}
+
+func (cfg *Config) NeedsConnection() bool {
+	// Return if the requested run connects to the API, offline subcommands don't
+	return cfg.Command != ExportCommand && cfg.Command != MCPCommand
+}
+
+func (cfg *Config) ServesStdio() bool {
+	// Return if stdout carries a protocol or machine-readable output, so logs must go to stderr
+	return cfg.Command == MCPCommand || cfg.Output != OutputFormatText
 }
 
 func (cfg *Config) validate() error {
@@ -91,21 +180,140 @@ func (cfg *Config) validate() error {
 		ModelFlag:   &cfg.Model,
 	}
 
-	missingVars := []FlagType{}
+	missingVars := []string{}
 
 	for name, valuePtr := range requiredKeys {
 		if *valuePtr == "" {
-			missingVars = append(missingVars, name)
+			missingVars = append(missingVars, fmt.Sprintf(ValueWithSourceText, name, cfg.GetSource(name)))
 		}
 	}
 
-	if len(missingVars) > 0 {
+	if len(missingVars) > 0 && cfg.NeedsConnection() {
+		sort.Strings(missingVars)
 		return fmt.Errorf(MissingRequiredFlagsOrEnvVarsErr, missingVars)
 	}
 
+	positiveKeys := map[FlagType]int{
+		TimeoutFlag:       cfg.Timeout,
+		RetriesFlag:       cfg.Retries,
+		ChannelBufferFlag: cfg.ChannelBuffer,
+		ToolTimeoutFlag:   cfg.ToolTimeout,
+		ToolParallelismFlag: cfg.ToolParallelism,
+		MaxToolIterationsFlag: cfg.MaxToolIterations,
+	}
+
+	for name, value := range positiveKeys {
+		if value <= 0 {
+			return fmt.Errorf(InvalidPositiveValueErr, name, value, cfg.GetSource(name))
+		}
+	}
+
+	for name, timeout := range cfg.ToolTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf(InvalidToolTimeoutErr, timeout, name, cfg.GetSource(ToolTimeoutsKey))
+		}
+	}
+
+	toolPolicies := []string{SafeToolPolicy, AskToolPolicy, DenyToolPolicy}
+	if !slices.Contains(toolPolicies, cfg.ToolPolicy) {
+		return fmt.Errorf(InvalidToolPolicyErr, cfg.ToolPolicy, ToolPolicyFlag, cfg.GetSource(ToolPolicyFlag), toolPolicies)
+	}
+	for name, policy := range cfg.ToolPolicies {
+		if !slices.Contains(toolPolicies, policy) {
+			return fmt.Errorf(InvalidToolPolicyErr, policy, name, cfg.GetSource(ToolPoliciesKey), toolPolicies)
+		}
+	}
+
+	for model, price := range cfg.Prices {
+		if price.Input < 0 || price.CachedInput < 0 || price.Output < 0 {
+			return fmt.Errorf(InvalidPriceErr, model, cfg.GetSource(PricesKey))
+		}
+	}
+
+	budgets := map[string]BudgetConfig{
+		BudgetSessionScope: cfg.Budgets.Session,
+		BudgetDayScope:     cfg.Budgets.Day,
+		BudgetProfileScope: cfg.Budgets.Profile,
+	}
+	for scope, budget := range budgets {
+		if budget.Tokens < 0 || budget.Cost < 0 {
+			return fmt.Errorf(InvalidBudgetErr, scope, cfg.GetSource(BudgetsKey))
+		}
+	}
+
+	if cfg.BudgetWarning <= 0 || cfg.BudgetWarning > 100 {
+		return fmt.Errorf(InvalidBudgetWarningErr, BudgetWarningFlag, cfg.BudgetWarning, cfg.GetSource(BudgetWarningFlag))
+	}
+
+	if cfg.Yes && cfg.DenyUnsafe {
+		return fmt.Errorf(ConflictingApprovalFlagsErr)
+	}
+
+	outputFormats := []string{OutputFormatText, OutputFormatJSON, OutputFormatNDJSON}
+	if !slices.Contains(outputFormats, cfg.Output) {
+		return fmt.Errorf(InvalidOutputFormatErr, cfg.Output, cfg.GetSource(OutputFlag), outputFormats)
+	}
+
 	return nil
 }
 
+func (cfg *Config) GetSource(name FlagType) string {
+	// Return the layer that supplied a config value
+	if source, exists := cfg.sources[name]; exists {
+		return source
+	}
+	return DefaultSource
+}
+
+func (cfg *Config) setSource(source string, names ...FlagType) {
+	// Record the layer that supplied config values
+	for _, name := range names {
+		cfg.sources[name] = source
+	}
+}
+
+func defaultToolPolicies() map[string]string {
+	// Return the policies of the built-in tools, which have no side effects
+	return map[string]string{
+		DefaultSafeTool: SafeToolPolicy,
+	}
+}
+
+func defaultPrices() map[string]PriceConfig {
+	// Return the list prices of text tokens of the realtime models, in USD per million tokens
+	return map[string]PriceConfig{
+		"gpt-realtime":                 {Input: 4.00, CachedInput: 0.40, Output: 16.00},
+		"gpt-realtime-mini":            {Input: 0.60, CachedInput: 0.06, Output: 2.40},
+		"gpt-4o-realtime-preview":      {Input: 5.00, CachedInput: 2.50, Output: 20.00},
+		"gpt-4o-mini-realtime-preview": {Input: 0.60, CachedInput: 0.30, Output: 2.40},
+	}
+}
+
+func (cfg *Config) GetPrice(model string) (PriceConfig, bool) {
+	// Return the price of a model, a price also applies to the dated snapshots of its model
+	if price, exists := cfg.Prices[model]; exists {
+		return price, true
+	}
+
+	matched := ""
+	for name := range cfg.Prices {
+		if strings.HasPrefix(model, name+ModelSnapshotSeparator) && len(name) > len(matched) {
+			matched = name
+		}
+	}
+	if matched == "" {
+		return PriceConfig{}, false
+	}
+	return cfg.Prices[matched], true
+}
+
+func (price PriceConfig) Cost(inputTokens int, cachedTokens int, outputTokens int) float64 {
+	// Return the cost of a response, cached input tokens are charged at their own price
+	uncachedTokens := inputTokens - cachedTokens
+	cost := float64(uncachedTokens)*price.Input + float64(cachedTokens)*price.CachedInput + float64(outputTokens)*price.Output
+	return cost / PriceTokensUnit
+}
+
 func (flagName FlagType) envVar() string {
 	// Convert flag name to environment variable name
 	return strings.ToUpper(strings.ReplaceAll(string(flagName), "-", "_"))
@@ -117,6 +325,16 @@ func (cfg *Config) setStringEnvVar(key FlagType, cfgPtr *string) {
 	envKey := key.envVar()
 	if envVal := os.Getenv(envKey); envVal != "" {
 		*cfgPtr = envVal
+		cfg.setSource(fmt.Sprintf(EnvSourceText, envKey), key)
+	}
+}
+
+func (cfg *Config) setStringListEnvVar(key FlagType, cfgPtr *[]string) {
+	// Set comma separated environment variable value to flag
+	envKey := key.envVar()
+	if envVal := os.Getenv(envKey); envVal != "" {
+		*cfgPtr = splitList(envVal)
+		cfg.setSource(fmt.Sprintf(EnvSourceText, envKey), key)
 	}
 }
 
@@ -126,6 +344,7 @@ func (cfg *Config) setIntEnvVar(key FlagType, cfgPtr *int) {
 	if envVal := os.Getenv(envKey); envVal != "" {
 		if intVal, _ := strconv.Atoi(envVal); intVal > 0 {
 			*cfgPtr = intVal
+			cfg.setSource(fmt.Sprintf(EnvSourceText, envKey), key)
 		}
 	}
 }
@@ -137,20 +356,60 @@ func (cfg *Config) setBoolEnvVar(key FlagType, cfgPtr *bool) {
 		switch envVal {
 		case "true", "1":
 			*cfgPtr = true
+			cfg.setSource(fmt.Sprintf(EnvSourceText, envKey), key)
 		case "false", "0":
 			*cfgPtr = false
+			cfg.setSource(fmt.Sprintf(EnvSourceText, envKey), key)
 		}
 	}
 }
 
+func (cfg *Config) GetSessionsDir() string {
+	// Return the directory of saved chat sessions
+	return filepath.Join(cfg.DataDir, SessionsDirName)
+}
+
+func (cfg *Config) GetLedgerPath() string {
+	// Return the path of the usage ledger shared by every profile
+	return filepath.Join(cfg.DataDir, LedgerFileName)
+}
+
+func (cfg *Config) GetLedgerProfile() string {
+	// Return the profile the usage is recorded under in the ledger
+	return firstNonEmpty(cfg.Profile, DefaultLedgerProfile)
+}
+
+func defaultDataDir() string {
+	// Return the XDG data directory of the app
+	if dataHome := os.Getenv(XDGDataHomeEnvVar); dataHome != "" {
+		return filepath.Join(dataHome, AppDirName)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return AppDirName
+	}
+	return filepath.Join(homeDir, DefaultDataHomeDir, AppDirName)
+}
+
 func (cfg *Config) GetConfigInfo() (string, error) {
 	configString := strings.Builder{}
 	configString.WriteString(ConfigInfoText)
-	jsonBytes, err := json.MarshalIndent(cfg, "", "  ")
+	jsonBytes, err := redact.MarshalIndent(cfg)
 	if err != nil {
 		return "", err
 	}
 	configString.WriteString(string(jsonBytes))
 	configString.WriteString("\n")
+
+	configString.WriteString(ConfigSourcesInfoText)
+	names := make([]string, 0, len(cfg.sources))
+	for name := range cfg.sources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		configString.WriteString(fmt.Sprintf(ConfigSourceLineText, name, cfg.sources[FlagType(name)]))
+	}
 	return configString.String(), nil
 }
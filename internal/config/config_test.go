@@ -0,0 +1,90 @@
+package config
+
+import (
+	"RTGPTGoCLI/internal/common"
+	"RTGPTGoCLI/pkg/logger"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeyNeverReachesOutput(t *testing.T) {
+	// Print the debug config, JSON payloads and every log level, and assert the API key never reaches stdout or stderr
+	const apiKey = "test-api-key-0123456789"
+	const fileAPIKey = "file-api-key-9876543210"
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	configFile := fmt.Sprintf("profiles:\n  work:\n    api_key: %s\n", fileAPIKey)
+	if err := os.WriteFile(configPath, []byte(configFile), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv(ApiKeyFlag.envVar(), apiKey)
+	t.Setenv(ConfigFlag.envVar(), configPath)
+	t.Setenv(DataDirFlag.envVar(), dir)
+
+	output := captureOutput(t, func() {
+		cfg, err := SetUp()
+		if err != nil {
+			t.Fatalf("SetUp() error = %v", err)
+		}
+		logger.SetDebugMode(true)
+
+		configInfo, err := cfg.GetConfigInfo()
+		if err != nil {
+			t.Fatalf("GetConfigInfo() error = %v", err)
+		}
+		fmt.Println(configInfo)
+		logger.Debug(configInfo)
+
+		common.PrintJSON([]byte(fmt.Sprintf(`{"api_key": %q, "headers": {"Authorization": "Bearer %s"}, "text": "key %s"}`, apiKey, apiKey, apiKey)))
+		common.PrintJSON([]byte(fmt.Sprintf(`{"profile": {"api_key": %q}}`, fileAPIKey)))
+
+		for _, msg := range []string{apiKey, "Authorization: Bearer " + apiKey, "profile key " + fileAPIKey} {
+			logger.Info(msg)
+			logger.Debug(msg)
+			logger.Warning(msg)
+			logger.Error(msg)
+		}
+	})
+
+	if !strings.Contains(output, ConfigInfoText) || !strings.Contains(output, "[ERROR]") {
+		t.Fatalf("output was not captured: %q", output)
+	}
+	for _, secret := range []string{apiKey, fileAPIKey} {
+		if strings.Contains(output, secret) {
+			t.Errorf("output leaks the API key %s:\n%s", secret, output)
+		}
+	}
+}
+
+func captureOutput(t *testing.T, fn func()) string {
+	// Run fn with stdout and stderr redirected to a file, with the loggers writing to it too
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	if err != nil {
+		t.Fatalf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = file, file
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		logger.InitLoggers()
+	}()
+	logger.InitLoggers()
+
+	fn()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	return string(data)
+}
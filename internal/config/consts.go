@@ -9,6 +9,44 @@ const (
 	TimeoutFlag FlagType = "timeout"
 	RetriesFlag FlagType = "retries"
 	ChannelBufferFlag FlagType = "channel-buffer"
+	PromptFlag  FlagType = "p"
+	OutputFlag  FlagType = "output"
+	DataDirFlag FlagType = "data-dir"
+	ResumeFlag  FlagType = "resume"
+	ConfigFlag  FlagType = "config"
+	ProfileFlag FlagType = "profile"
+	InstructionsFlag FlagType = "instructions"
+	ToolsFlag   FlagType = "tools"
+	ToolsDirFlag FlagType = "tools-dir"
+	ToolTimeoutFlag FlagType = "tool-timeout"
+	ToolParallelismFlag FlagType = "tool-parallelism"
+	MaxToolIterationsFlag FlagType = "max-tool-iterations"
+	ToolPolicyFlag FlagType = "tool-policy"
+	YesFlag     FlagType = "yes"
+	DenyUnsafeFlag FlagType = "deny-unsafe"
+	ColorFlag   FlagType = "color"
+	BudgetWarningFlag FlagType = "budget-warning"
+)
+
+const (
+	// Subcommands, given as positional arguments after the flags
+	ExportCommand = "export"
+	MCPCommand = "mcp"
+	MCPServeSubcommand = "serve"
+)
+
+const (
+	// Output formats
+	OutputFormatText   = "text"
+	OutputFormatJSON   = "json"
+	OutputFormatNDJSON = "ndjson"
+)
+
+const (
+	// Tool policies
+	SafeToolPolicy = "safe"
+	AskToolPolicy  = "ask"
+	DenyToolPolicy = "deny"
 )
 
 const (
@@ -20,12 +58,81 @@ const (
 	DefaultDebug = false
 	DefaultRetries = 3
 	DefaultChannelBuffer = 100
+	DefaultOutput = OutputFormatText
+	DefaultInstructions = ""
+	DefaultColor = true
+	DefaultToolTimeout = 30
+	DefaultToolParallelism = 4
+	DefaultMaxToolIterations = 10
+	DefaultToolPolicy = AskToolPolicy
+	DefaultSafeTool = "multiply"
+	DefaultCurrency = "USD"
+	DefaultBudgetWarning = 80
+	DefaultLedgerProfile = "default"
+)
+
+const (
+	// Budget scopes
+	BudgetSessionScope = "session"
+	BudgetDayScope = "day"
+	BudgetProfileScope = "profile"
+)
+
+const (
+	// Pricing constants
+	PriceTokensUnit = 1_000_000
+	ModelSnapshotSeparator = "-"
+)
+
+const (
+	// Config file only keys
+	MCPServersKey FlagType = "mcp-servers"
+	CustomToolsKey FlagType = "custom-tools"
+	ToolTimeoutsKey FlagType = "tool-timeouts"
+	ToolPoliciesKey FlagType = "tool-policies"
+	PricesKey FlagType = "prices"
+	CurrencyKey FlagType = "currency"
+	BudgetsKey FlagType = "budgets"
+)
+
+const (
+	// Config file constants
+	ConfigFileName = "config.yaml"
+	ToolsDirName = "tools"
+)
+
+const (
+	// Config value sources
+	DefaultSource = "default"
+	FileSourceText = "config file %s"
+	ProfileSourceText = "profile %s"
+	EnvSourceText = "env %s"
+	FlagSourceText = "flag -%s"
+)
+
+const (
+	// Data directory constants
+	AppDirName = "rtgptcli"
+	SessionsDirName = "sessions"
+	LedgerFileName = "usage.json"
+	XDGDataHomeEnvVar = "XDG_DATA_HOME"
+	DefaultDataHomeDir = ".local/share"
 )
 
 const (
 	// Error messages
 	MissingOrErrorLoadingEnvFileErr = "no .env file found or error loading .env file, proceeding with existing environment variables."
 	MissingRequiredFlagsOrEnvVarsErr = "missing the following required flags or environment variables: %v"
+	InvalidOutputFormatErr = "invalid output format %q (from %s), expected one of: %v"
+	InvalidPositiveValueErr = "invalid %s %d (from %s), expected a positive number"
+	InvalidToolTimeoutErr = "invalid timeout %d of tool %s (from %s), expected a positive number"
+	InvalidToolPolicyErr = "invalid policy %q of %s (from %s), expected one of: %v"
+	InvalidPriceErr = "invalid price of model %s (from %s), expected non-negative numbers"
+	InvalidBudgetErr = "invalid %s budget (from %s), expected non-negative tokens and cost"
+	InvalidBudgetWarningErr = "invalid %s %d (from %s), expected a percentage between 1 and 100"
+	ConflictingApprovalFlagsErr = "-yes and -deny-unsafe cannot be used together"
+	FailedToLoadConfigFileErr = "failed to load config file %s: %v"
+	UnknownProfileErr = "unknown profile %q in config file %s"
 )
 
 const (
@@ -37,9 +144,29 @@ const (
 	RetriesFlagUsageText = "Number of retries for failed requests"
 	ChannelBufferFlagUsageText = "Buffer size for channels"
 	DebugFlagUsageText = "Enable debug mode"
+	ConfigFlagUsageText = "Path of the YAML config file (default $XDG_CONFIG_HOME/rtgptcli/config.yaml)"
+	ProfileFlagUsageText = "Named profile of the config file to use"
+	InstructionsFlagUsageText = "Session instructions (system prompt)"
+	ToolsFlagUsageText = "Comma separated list of enabled tools (default all)"
+	ToolsDirFlagUsageText = "Directory of executable tool plugins (default $XDG_CONFIG_HOME/rtgptcli/tools)"
+	ToolTimeoutFlagUsageText = "Default timeout in seconds of a tool call"
+	ToolParallelismFlagUsageText = "Max function calls of a response executed at the same time"
+	MaxToolIterationsFlagUsageText = "Max rounds of function calls in a single turn"
+	ToolPolicyFlagUsageText = "Policy of tools without their own policy: safe, ask or deny"
+	YesFlagUsageText = "Approve every tool call that needs approval without asking"
+	DenyUnsafeFlagUsageText = "Deny every tool call that needs approval without asking (default of one-shot runs)"
+	ColorFlagUsageText = "Enable coloured output"
+	BudgetWarningFlagUsageText = "Percentage of a budget that shows a warning once used"
+	DataDirFlagUsageText = "Directory for local data such as saved sessions"
+	ResumeFlagUsageText = "Resume a saved chat session by its ID"
+	OutputFlagUsageText = "Output format of one-shot runs: text, json or ndjson"
+	PromptFlagUsageText = "Run a single prompt non-interactively and print the response (stdin is appended when piped)"
 )
 
 const (
 	// General strings
 	ConfigInfoText = "\nCurrent Configuration:\n"
+	ConfigSourcesInfoText = "\nValue Sources:\n"
+	ConfigSourceLineText = "  %s: %s\n"
+	ValueWithSourceText = "%s (last set by %s)"
 )
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"RTGPTGoCLI/pkg/redact"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (cfg *Config) loadFromFile(setFlags map[FlagType]string) error {
+	// Load the config file and its selected profile, when a config file exists
+	cfg.ConfigPath = firstNonEmpty(setFlags[ConfigFlag], os.Getenv(ConfigFlag.envVar()))
+	explicitPath := cfg.ConfigPath != ""
+	if !explicitPath {
+		cfg.ConfigPath = defaultConfigPath()
+	}
+
+	fileConfig, err := readConfigFile(cfg.ConfigPath)
+	if errors.Is(err, os.ErrNotExist) && !explicitPath {
+		fileConfig = &FileConfig{}
+	} else if err != nil {
+		return fmt.Errorf(FailedToLoadConfigFileErr, cfg.ConfigPath, err)
+	} else {
+		cfg.applyFileSettings(fileConfig.FileSettings, fmt.Sprintf(FileSourceText, cfg.ConfigPath))
+	}
+
+	cfg.Profile = firstNonEmpty(setFlags[ProfileFlag], os.Getenv(ProfileFlag.envVar()), fileConfig.DefaultProfile)
+	if cfg.Profile == "" {
+		return nil
+	}
+
+	profile, exists := fileConfig.Profiles[cfg.Profile]
+	if !exists {
+		return fmt.Errorf(UnknownProfileErr, cfg.Profile, cfg.ConfigPath)
+	}
+	cfg.applyFileSettings(profile, fmt.Sprintf(ProfileSourceText, cfg.Profile))
+	return nil
+}
+
+func readConfigFile(path string) (*FileConfig, error) {
+	// Read and parse a YAML config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileConfig := &FileConfig{}
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(fileConfig); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	fileConfig.registerSecrets()
+	return fileConfig, nil
+}
+
+func (fileConfig *FileConfig) registerSecrets() {
+	// Register the API keys of every layer of the file, so unused profiles are masked too
+	layers := []FileSettings{fileConfig.FileSettings}
+	for _, profile := range fileConfig.Profiles {
+		layers = append(layers, profile)
+	}
+
+	for _, layer := range layers {
+		if layer.APIKey != nil {
+			redact.AddSecret(os.ExpandEnv(*layer.APIKey))
+		}
+	}
+}
+
+func (cfg *Config) applyFileSettings(settings FileSettings, source string) {
+	// Apply the values set in a config file layer
+	setString(cfg, &cfg.APIKey, settings.APIKey, ApiKeyFlag, source)
+	setString(cfg, &cfg.BaseURL, settings.BaseURL, BaseURLFlag, source)
+	setString(cfg, &cfg.Model, settings.Model, ModelFlag, source)
+	setString(cfg, &cfg.Instructions, settings.Instructions, InstructionsFlag, source)
+	setString(cfg, &cfg.Output, settings.Output, OutputFlag, source)
+	setString(cfg, &cfg.DataDir, settings.DataDir, DataDirFlag, source)
+	setValue(cfg, &cfg.Tools, settings.Tools, ToolsFlag, source)
+	setString(cfg, &cfg.ToolsDir, settings.ToolsDir, ToolsDirFlag, source)
+	setValue(cfg, &cfg.ToolTimeout, settings.ToolTimeout, ToolTimeoutFlag, source)
+	setValue(cfg, &cfg.ToolParallelism, settings.ToolParallelism, ToolParallelismFlag, source)
+	setValue(cfg, &cfg.MaxToolIterations, settings.MaxToolIterations, MaxToolIterationsFlag, source)
+	cfg.addMCPServers(settings.MCPServers, source)
+	cfg.addCustomTools(settings.CustomTools, source)
+	cfg.addToolTimeouts(settings.ToolTimeouts, source)
+	setString(cfg, &cfg.ToolPolicy, settings.ToolPolicy, ToolPolicyFlag, source)
+	cfg.addToolPolicies(settings.ToolPolicies, source)
+	cfg.addPrices(settings.Prices, source)
+	setString(cfg, &cfg.Currency, settings.Currency, CurrencyKey, source)
+	setValue(cfg, &cfg.Budgets.Session, settings.Budgets.Session, BudgetsKey, source)
+	setValue(cfg, &cfg.Budgets.Day, settings.Budgets.Day, BudgetsKey, source)
+	setValue(cfg, &cfg.Budgets.Profile, settings.Budgets.Profile, BudgetsKey, source)
+	setValue(cfg, &cfg.BudgetWarning, settings.BudgetWarning, BudgetWarningFlag, source)
+
+	setValue(cfg, &cfg.Timeout, settings.Timeout, TimeoutFlag, source)
+	setValue(cfg, &cfg.Retries, settings.Retries, RetriesFlag, source)
+	setValue(cfg, &cfg.ChannelBuffer, settings.ChannelBuffer, ChannelBufferFlag, source)
+
+	setValue(cfg, &cfg.Debug, settings.Debug, DebugFlag, source)
+	setValue(cfg, &cfg.UI.Color, settings.UI.Color, ColorFlag, source)
+}
+
+func (cfg *Config) addMCPServers(servers map[string]MCPServerConfig, source string) {
+	// Add the MCP servers of a file layer, a profile replaces servers of the same name
+	for name, server := range servers {
+		server.Command = os.ExpandEnv(server.Command)
+		for i, arg := range server.Args {
+			server.Args[i] = os.ExpandEnv(arg)
+		}
+		for key, value := range server.Env {
+			server.Env[key] = os.ExpandEnv(value)
+		}
+		cfg.MCPServers[name] = server
+		cfg.setSource(source, MCPServersKey)
+	}
+}
+
+func (cfg *Config) addCustomTools(tools map[string]CustomToolConfig, source string) {
+	// Add the declarative tools of a file layer, a profile replaces tools of the same name
+	for name, tool := range tools {
+		cfg.CustomTools[name] = tool
+		cfg.setSource(source, CustomToolsKey)
+	}
+}
+
+func (cfg *Config) addToolTimeouts(timeouts map[string]int, source string) {
+	// Add the per tool timeouts of a file layer, a profile replaces timeouts of the same tool
+	for name, timeout := range timeouts {
+		cfg.ToolTimeouts[name] = timeout
+		cfg.setSource(source, ToolTimeoutsKey)
+	}
+}
+
+func (cfg *Config) addToolPolicies(policies map[string]string, source string) {
+	// Add the per tool policies of a file layer, a profile replaces policies of the same tool
+	for name, policy := range policies {
+		cfg.ToolPolicies[name] = policy
+		cfg.setSource(source, ToolPoliciesKey)
+	}
+}
+
+func (cfg *Config) addPrices(prices map[string]PriceConfig, source string) {
+	// Add the model prices of a file layer, replacing the default or earlier price of the same model
+	for model, price := range prices {
+		cfg.Prices[model] = price
+		cfg.setSource(source, PricesKey)
+	}
+}
+
+func setString(cfg *Config, cfgPtr *string, value *string, name FlagType, source string) {
+	// Set a string value from a file layer, expanding environment variables such as ${API_KEY}
+	if value == nil {
+		return
+	}
+	expanded := os.ExpandEnv(*value)
+	setValue(cfg, cfgPtr, &expanded, name, source)
+}
+
+func setValue[T any](cfg *Config, cfgPtr *T, value *T, name FlagType, source string) {
+	// Set a value from a file layer when it is present
+	if value == nil {
+		return
+	}
+	*cfgPtr = *value
+	cfg.setSource(source, name)
+}
+
+func defaultConfigPath() string {
+	// Return the XDG config file path of the app
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(AppDirName, ConfigFileName)
+	}
+	return filepath.Join(configDir, AppDirName, ConfigFileName)
+}
+
+func defaultToolsDir() string {
+	// Return the XDG tool plugins directory of the app
+	return filepath.Join(filepath.Dir(defaultConfigPath()), ToolsDirName)
+}
+
+func firstNonEmpty(values ...string) string {
+	// Return the first non empty value
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func splitList(value string) []string {
+	// Split a comma separated list, dropping empty entries
+	list := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+func (listValue *stringListValue) String() string {
+	// Return the flag value as a comma separated list
+	if listValue.values == nil {
+		return ""
+	}
+	return strings.Join(*listValue.values, ",")
+}
+
+func (listValue *stringListValue) Set(value string) error {
+	// Set the flag value from a comma separated list
+	*listValue.values = splitList(value)
+	return nil
+}
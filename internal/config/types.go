@@ -8,6 +8,140 @@ type Config struct {
 	Timeout int
 	Retries int
 	ChannelBuffer int
+	Prompt  string
+	Output  string
+	DataDir string
+	Resume  string
+	Instructions string
+	Tools   []string
+	ToolsDir string
+	ToolTimeout int
+	ToolTimeouts map[string]int
+	ToolPolicy string
+	ToolPolicies map[string]string
+	Yes bool
+	DenyUnsafe bool
+	ToolParallelism int
+	MaxToolIterations int
+	MCPServers map[string]MCPServerConfig
+	CustomTools map[string]CustomToolConfig
+	Prices  map[string]PriceConfig
+	Currency string
+	Budgets BudgetsConfig
+	BudgetWarning int
+	UI      UIConfig
+
+	ConfigPath string
+	Profile    string
+
+	Command     string
+	CommandArgs []string
+
+	sources map[FlagType]string
+}
+
+type UIConfig struct {
+	// UI preferences
+	Color bool
 }
 
 type FlagType string
+
+type FileConfig struct {
+	// Config file struct, top level settings plus named profiles
+	FileSettings   `yaml:",inline"`
+	DefaultProfile string                  `yaml:"default_profile"`
+	Profiles       map[string]FileSettings `yaml:"profiles"`
+}
+
+type FileSettings struct {
+	// Settings of a config file layer, nil values are left untouched
+	APIKey        *string          `yaml:"api_key"`
+	BaseURL       *string          `yaml:"base_url"`
+	Model         *string          `yaml:"model"`
+	Instructions  *string          `yaml:"instructions"`
+	Tools         *[]string        `yaml:"tools"`
+	ToolsDir      *string          `yaml:"tools_dir"`
+	ToolTimeout   *int             `yaml:"tool_timeout"`
+	ToolTimeouts  map[string]int   `yaml:"tool_timeouts"`
+	ToolPolicy    *string          `yaml:"tool_policy"`
+	ToolPolicies  map[string]string `yaml:"tool_policies"`
+	ToolParallelism *int           `yaml:"tool_parallelism"`
+	MaxToolIterations *int         `yaml:"max_tool_iterations"`
+	MCPServers    map[string]MCPServerConfig `yaml:"mcp_servers"`
+	CustomTools   map[string]CustomToolConfig `yaml:"custom_tools"`
+	Prices        map[string]PriceConfig `yaml:"prices"`
+	Currency      *string          `yaml:"currency"`
+	Budgets       FileBudgetsSettings `yaml:"budgets"`
+	BudgetWarning *int             `yaml:"budget_warning"`
+	Timeout       *int             `yaml:"timeout"`
+	Retries       *int             `yaml:"retries"`
+	ChannelBuffer *int             `yaml:"channel_buffer"`
+	Debug         *bool            `yaml:"debug"`
+	Output        *string          `yaml:"output"`
+	DataDir       *string          `yaml:"data_dir"`
+	UI            FileUISettings   `yaml:"ui"`
+}
+
+type FileUISettings struct {
+	// UI preferences of a config file layer
+	Color *bool `yaml:"color"`
+}
+
+type stringListValue struct {
+	// Comma separated list flag value
+	values *[]string
+}
+
+type MCPServerConfig struct {
+	// MCP server launched over stdio
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+	Timeout int               `yaml:"timeout"`
+}
+
+type CustomToolConfig struct {
+	// Declarative tool, exactly one of Shell, HTTP or Text is its action
+	Description string                 `yaml:"description"`
+	Parameters  map[string]interface{} `yaml:"parameters"`
+	Shell       string                 `yaml:"shell"`
+	HTTP        *CustomToolHTTPConfig  `yaml:"http"`
+	Text        *string                `yaml:"text"`
+	Timeout     int                    `yaml:"timeout"`
+}
+
+type PriceConfig struct {
+	// Price of a model per million text tokens, cached input tokens are a part of the input tokens
+	Input       float64 `yaml:"input" json:"input"`
+	CachedInput float64 `yaml:"cached_input" json:"cached_input"`
+	Output      float64 `yaml:"output" json:"output"`
+}
+
+type BudgetConfig struct {
+	// Budget limit in tokens and in cost, a zero value has no limit
+	Tokens int     `yaml:"tokens" json:"tokens,omitempty"`
+	Cost   float64 `yaml:"cost" json:"cost,omitempty"`
+}
+
+type BudgetsConfig struct {
+	// Budgets of the current session, of the current day and of the profile over every run
+	Session BudgetConfig
+	Day     BudgetConfig
+	Profile BudgetConfig
+}
+
+type FileBudgetsSettings struct {
+	// Budgets of a config file layer, nil budgets are left untouched
+	Session *BudgetConfig `yaml:"session"`
+	Day     *BudgetConfig `yaml:"day"`
+	Profile *BudgetConfig `yaml:"profile"`
+}
+
+type CustomToolHTTPConfig struct {
+	// HTTP request template against a local endpoint
+	Method  string            `yaml:"method"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
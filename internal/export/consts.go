@@ -0,0 +1,77 @@
+package export
+
+import "os"
+
+const (
+	// Export formats
+	ExportFormatMarkdown      = "markdown"
+	ExportFormatMarkdownShort = "md"
+	ExportFormatHTML          = "html"
+	ExportFormatJSON          = "json"
+)
+
+const (
+	// Export constants
+	ExportTimeLayout      = "2006-01-02 15:04:05"
+	ExportFilePermissions os.FileMode = 0o600
+	ExportUserLabel       = "You"
+	ExportChatLabel       = "Chat"
+	ExportFunctionCallLabel   = "Function call"
+	ExportFunctionResultLabel = "Function result"
+)
+
+const (
+	// Errors
+	ExportUnknownFormatErr = "unknown export format %q, expected one of: markdown, md, html, json"
+	ExportRenderErr        = "failed to render transcript: %v"
+	ExportWriteErr         = "failed to write transcript to %s: %v"
+)
+
+const (
+	// Markdown templates
+	MarkdownHeaderTemplate = "# %s\n\n- Session: `%s`\n- Model: `%s`\n- Created: %s\n- Updated: %s\n- Tokens: %s\n- Cost: %s\n\n---\n\n"
+	MarkdownMessageTemplate = "**%s** · %s\n\n%s\n\n"
+	MarkdownCodeTemplate    = "**%s** `%s` · %s\n\n```json\n%s\n```\n\n"
+)
+
+const (
+	// HTML template of a standalone transcript page
+	HTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+header dl { display: grid; grid-template-columns: max-content auto; gap: 0.25rem 1rem; color: #57606a; }
+.entry { margin: 1rem 0; }
+.label { font-weight: 600; }
+.time { color: #8c959f; font-size: 0.85em; margin-left: 0.5rem; }
+.user .label { color: #0969da; }
+.assistant .label { color: #1a7f37; }
+.function .label { color: #9a6700; }
+.text { white-space: pre-wrap; margin-top: 0.25rem; }
+pre { background: #f6f8fa; padding: 0.75rem; border-radius: 6px; overflow-x: auto; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<dl>
+<dt>Session</dt><dd>{{.ID}}</dd>
+<dt>Model</dt><dd>{{.Model}}</dd>
+<dt>Created</dt><dd>{{.Created}}</dd>
+<dt>Updated</dt><dd>{{.Updated}}</dd>
+<dt>Tokens</dt><dd>{{.Tokens}}</dd>
+<dt>Cost</dt><dd>{{.Cost}}</dd>
+</dl>
+</header>
+{{range .Entries}}<div class="entry {{.Class}}">
+<span class="label">{{.Label}}</span>{{if .Name}} <code>{{.Name}}</code>{{end}}<span class="time">{{.Time}}</span>
+{{if .Code}}<pre><code>{{.Body}}</code></pre>{{else}}<div class="text">{{.Body}}</div>{{end}}
+</div>
+{{end}}</body>
+</html>
+`
+)
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"RTGPTGoCLI/internal/sessions"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+)
+
+func WriteFile(session *sessions.Session, format string, path string) error {
+	// Render session in the given format and write it to path
+	content, err := Render(session, format)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, content, ExportFilePermissions); err != nil {
+		return fmt.Errorf(ExportWriteErr, path, err)
+	}
+	return nil
+}
+
+func Render(session *sessions.Session, format string) ([]byte, error) {
+	// Render session in the given format
+	switch strings.ToLower(format) {
+	case ExportFormatMarkdown, ExportFormatMarkdownShort:
+		return renderMarkdown(newTranscript(session)), nil
+	case ExportFormatHTML:
+		return renderHTML(newTranscript(session))
+	case ExportFormatJSON:
+		return renderJSON(session)
+	default:
+		return nil, fmt.Errorf(ExportUnknownFormatErr, format)
+	}
+}
+
+func newTranscript(session *sessions.Session) Transcript {
+	// Build the render-ready transcript of a session
+	summary := session.Summary()
+	usage := session.GetUsage()
+	items := session.GetItems()
+	functionNames := map[string]string{}
+	entries := make([]Entry, 0, len(items))
+
+	for _, item := range items {
+		entry := Entry{Time: item.Timestamp.Format(ExportTimeLayout)}
+
+		switch item.Type {
+		case sessions.SessionFunctionCallItemType:
+			functionNames[item.CallID] = item.Name
+			entry.Class, entry.Label, entry.Name = "function", ExportFunctionCallLabel, item.Name
+			entry.Body, entry.Code = prettyJSON(item.Arguments), true
+		case sessions.SessionFunctionCallOutputItemType:
+			entry.Class, entry.Label, entry.Name = "function", ExportFunctionResultLabel, functionNames[item.CallID]
+			entry.Body, entry.Code = prettyJSON(item.Output), true
+		default:
+			entry.Class, entry.Label = "assistant", ExportChatLabel
+			if item.Role == sessions.SessionUserRole {
+				entry.Class, entry.Label = "user", ExportUserLabel
+			}
+			entry.Body = item.Text
+		}
+		entries = append(entries, entry)
+	}
+
+	return Transcript{
+		ID:      session.ID,
+		Title:   summary.Title,
+		Model:   session.Model,
+		Created: session.CreatedAt.Format(ExportTimeLayout),
+		Updated: summary.UpdatedAt.Format(ExportTimeLayout),
+		Tokens:  usage.TokensText(),
+		Cost:    usage.CostText(),
+		Entries: entries,
+	}
+}
+
+func renderMarkdown(transcript Transcript) []byte {
+	// Render transcript as Markdown
+	markdown := strings.Builder{}
+	markdown.WriteString(fmt.Sprintf(MarkdownHeaderTemplate, transcript.Title, transcript.ID, transcript.Model, transcript.Created, transcript.Updated, transcript.Tokens, transcript.Cost))
+
+	for _, entry := range transcript.Entries {
+		if entry.Code {
+			markdown.WriteString(fmt.Sprintf(MarkdownCodeTemplate, entry.Label, entry.Name, entry.Time, entry.Body))
+			continue
+		}
+		markdown.WriteString(fmt.Sprintf(MarkdownMessageTemplate, entry.Label, entry.Time, entry.Body))
+	}
+	return []byte(markdown.String())
+}
+
+func renderHTML(transcript Transcript) ([]byte, error) {
+	// Render transcript as a standalone HTML page
+	htmlTemplate, err := template.New("transcript").Parse(HTMLTemplate)
+	if err != nil {
+		return nil, fmt.Errorf(ExportRenderErr, err)
+	}
+
+	var buffer bytes.Buffer
+	if err := htmlTemplate.Execute(&buffer, transcript); err != nil {
+		return nil, fmt.Errorf(ExportRenderErr, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func renderJSON(session *sessions.Session) ([]byte, error) {
+	// Render the full session as JSON
+	jsonBytes, err := session.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf(ExportRenderErr, err)
+	}
+	return append(jsonBytes, '\n'), nil
+}
+
+func prettyJSON(raw string) string {
+	// Indent JSON strings, keeping anything else as is
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, []byte(raw), "", "  "); err != nil {
+		return raw
+	}
+	return buffer.String()
+}
@@ -0,0 +1,23 @@
+package export
+
+type Entry struct {
+	// Transcript entry, one per rendered session item
+	Class string
+	Label string
+	Name  string
+	Time  string
+	Body  string
+	Code  bool
+}
+
+type Transcript struct {
+	// Transcript struct, the render-ready view of a session
+	ID      string
+	Title   string
+	Model   string
+	Created string
+	Updated string
+	Tokens  string
+	Cost    string
+	Entries []Entry
+}
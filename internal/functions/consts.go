@@ -3,17 +3,61 @@ package functions
 const (
 	// Errors
 	FunctionMissingRequiredParameterErr = "missing required parameter: %s"
-	FunctionInvalidParameterTypeErr = "invalid parameter type: expected array of numbers, got %v"
-	InvalidParameterErr = "invalid parameter: %s"
+	FunctionInvalidParameterErr = "invalid parameter %s: expected %s, got %v"
+	FunctionUnsupportedArgsTypeErr = "unsupported arguments type %s, expected a struct"
+	FunctionInvalidArgumentsErr = "invalid arguments for %s: %s"
+	FunctionArgumentsNotJSONErr = "arguments are not a valid JSON object: %v"
+	FunctionInvalidSchemaErr = "invalid parameters schema of %s, its arguments can't be validated: %v"
+)
+
+const (
+	// Validation messages
+	ValidationTypeErr = "expected %s, got %s"
+	ValidationRequiredErr = "is required"
+	ValidationEnumErr = "must be one of %v"
+	ValidationMinimumErr = "must be >= %v"
+	ValidationMaximumErr = "must be <= %v"
+	ValidationMinItemsErr = "must have at least %v items"
+	ValidationMaxItemsErr = "must have at most %v items"
+	ValidationRootPath = "arguments"
+	ValidationErrorCode = "invalid_arguments"
+	ValidationRetryHint = "Fix the arguments listed in details and call the function again."
 )
 
 const (
 	// general function constants
-	FunctionRequiredValue = true
 	FunctionTypeText = "function"
-	FunctionOperationText = "operation"
-	FunctionNumbersParamText = "numbers"
 	FunctionNumberTypeText = "number"
 	FunctionArrayTypeText = "array"
 	FunctionObjectTypeText = "object"
+	FunctionStringTypeText = "string"
+	FunctionIntegerTypeText = "integer"
+	FunctionBooleanTypeText = "boolean"
+	FunctionNullTypeText = "null"
+)
+
+const (
+	// Schema struct tags
+	SchemaJSONTag = "json"
+	SchemaDescriptionTag = "description"
+	SchemaEnumTag = "enum"
+	SchemaMinimumTag = "minimum"
+	SchemaMaximumTag = "maximum"
+	SchemaMinItemsTag = "minItems"
+	SchemaMaxItemsTag = "maxItems"
+	SchemaRequiredTag = "required"
+)
+
+const (
+	// Schema keywords
+	SchemaTypeKey = "type"
+	SchemaDescriptionKey = "description"
+	SchemaEnumKey = "enum"
+	SchemaMinimumKey = "minimum"
+	SchemaMaximumKey = "maximum"
+	SchemaMinItemsKey = "minItems"
+	SchemaMaxItemsKey = "maxItems"
+	SchemaItemsKey = "items"
+	SchemaPropertiesKey = "properties"
+	SchemaRequiredKey = "required"
 )
@@ -0,0 +1,36 @@
+package declarative
+
+const (
+	// Declarative tool constants
+	DeclarativeShell = "sh"
+	DeclarativeShellArg = "-c"
+	DeclarativeDefaultHTTPMethod = "GET"
+	DeclarativeMaxOutputLength = 64 * 1024
+	DeclarativeMaxRedirects = 10
+	DeclarativePathEscapeFunc = "urlpath"
+	DeclarativeQueryEscapeFunc = "urlquery"
+)
+
+// Hosts HTTP tools are allowed to call
+var DeclarativeLocalHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+const (
+	// Log messages
+	DeclarativeExecutingMsg = "Executing declarative tool %s with params: %+v"
+	DeclarativeStderrMsg = "Declarative tool %s stderr: %s"
+)
+
+const (
+	// Errors
+	DeclarativeNoActionErr = "tool %s needs exactly one action: shell, http or text"
+	DeclarativeInvalidTemplateErr = "invalid %s template of tool %s: %v"
+	DeclarativeInvalidParametersErr = "invalid parameters of tool %s: %v"
+	DeclarativeRenderErr = "failed to render tool %s: %v"
+	DeclarativeShellErr = "tool %s failed (exit code %d): %s"
+	DeclarativeRunErr = "failed to run tool %s: %v"
+	DeclarativeNonLocalURLErr = "tool %s can only call local endpoints, got host %q"
+	DeclarativeNonLocalRedirectErr = "can only follow redirects to local endpoints, got host %q"
+	DeclarativeTooManyRedirectsErr = "stopped after %d redirects"
+	DeclarativeQuotedArgumentErr = "invalid shell template of tool %s: arguments are shell quoted, so {{%s}} can't be inside quotes or backticks"
+	DeclarativeHTTPStatusErr = "tool %s got HTTP status %d: %s"
+)
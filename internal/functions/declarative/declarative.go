@@ -0,0 +1,483 @@
+package declarative
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"RTGPTGoCLI/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+func LoadTools(definitions map[string]config.CustomToolConfig, defaultTimeout time.Duration) ([]*Tool, []*errorhandler.AppError) {
+	// Compile the declarative tools of the config file
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := []*Tool{}
+	appErrs := []*errorhandler.AppError{}
+	for _, name := range names {
+		tool, err := NewTool(name, definitions[name], defaultTimeout)
+		if err != nil {
+			appErrs = append(appErrs, errorhandler.NewAppError(errorhandler.WarningLevel, err.Error(), err))
+			continue
+		}
+		tools = append(tools, tool)
+	}
+	return tools, appErrs
+}
+
+func NewTool(name string, definition config.CustomToolConfig, defaultTimeout time.Duration) (*Tool, error) {
+	// Compile a declarative tool definition
+	parameters, err := parseParameters(definition.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf(DeclarativeInvalidParametersErr, name, err)
+	}
+
+	action, err := newAction(name, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultTimeout
+	if definition.Timeout > 0 {
+		timeout = time.Duration(definition.Timeout) * time.Second
+	}
+
+	return &Tool{
+		name:        name,
+		description: definition.Description,
+		parameters:  parameters,
+		timeout:     timeout,
+		action:      action,
+	}, nil
+}
+
+func (t *Tool) Execute(ctx context.Context, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Run the tool action with the call arguments
+	logger.Debug(fmt.Sprintf(DeclarativeExecutingMsg, t.name, params))
+
+	return t.action.run(ctx, t, params)
+}
+
+func (t *Tool) Timeout() time.Duration {
+	// Return the call timeout of the tool
+	return t.timeout
+}
+
+func (t *Tool) GetMetadata() functions.FunctionPayload {
+	// Get tool metadata from its JSON schema parameters
+	return functions.FunctionPayload{
+		Name:        t.name,
+		Description: t.description,
+		Parameters:  functions.ParametersFromSchema(t.parameters),
+	}
+}
+
+func (t *Tool) ConvertToOpenAITool() functions.OpenAIToolsPayload {
+	// Convert tool to OpenAI tool
+	return functions.OpenAIToolsPayload{
+		Type:        functions.FunctionTypeText,
+		Name:        t.name,
+		Description: t.description,
+		Parameters:  t.parameters,
+	}
+}
+
+func newAction(name string, definition config.CustomToolConfig) (action, error) {
+	// Compile the single action of a tool definition
+	actions := 0
+	for _, set := range []bool{definition.Shell != "", definition.HTTP != nil, definition.Text != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return nil, fmt.Errorf(DeclarativeNoActionErr, name)
+	}
+
+	switch {
+	case definition.Shell != "":
+		command, err := parseShellTemplate(name, definition.Shell)
+		if err != nil {
+			return nil, err
+		}
+		return &shellAction{command: command}, nil
+	case definition.HTTP != nil:
+		return newHTTPAction(name, definition.HTTP)
+	default:
+		text, err := parseTemplate(name, "text", *definition.Text)
+		if err != nil {
+			return nil, err
+		}
+		return &textAction{text: text}, nil
+	}
+}
+
+func newHTTPAction(name string, definition *config.CustomToolHTTPConfig) (action, error) {
+	// Compile an HTTP request template
+	method := strings.ToUpper(definition.Method)
+	if method == "" {
+		method = DeclarativeDefaultHTTPMethod
+	}
+
+	urlTemplate, err := parseURLTemplate(name, definition.URL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := parseTemplate(name, "body", definition.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]*template.Template, len(definition.Headers))
+	for key, value := range definition.Headers {
+		if headers[key], err = parseTemplate(name, "header", value); err != nil {
+			return nil, err
+		}
+	}
+
+	client := &http.Client{CheckRedirect: checkLocalRedirect}
+	return &httpAction{client: client, method: method, url: urlTemplate, headers: headers, body: body}, nil
+}
+
+func checkLocalRedirect(request *http.Request, via []*http.Request) error {
+	// Follow redirects only to local endpoints, so a local endpoint can't send the request off the machine
+	if len(via) >= DeclarativeMaxRedirects {
+		return fmt.Errorf(DeclarativeTooManyRedirectsErr, DeclarativeMaxRedirects)
+	}
+	if !isLocalHost(request.URL.Hostname()) {
+		return fmt.Errorf(DeclarativeNonLocalRedirectErr, request.URL.Hostname())
+	}
+	return nil
+}
+
+func isLocalHost(host string) bool {
+	// Return if HTTP tools are allowed to call the host
+	return slices.Contains(DeclarativeLocalHosts, host)
+}
+
+func (a *shellAction) run(ctx context.Context, tool *Tool, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Run the shell command, arguments are inserted shell quoted
+	quoted := templateData(tool, params)
+	for key, value := range quoted {
+		quoted[key] = shellQuote(valueText(value))
+	}
+
+	command, appErr := render(tool, a.command, quoted)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, DeclarativeShell, DeclarativeShellArg, command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	if stderr.Len() > 0 {
+		logger.Debug(fmt.Sprintf(DeclarativeStderrMsg, tool.name, strings.TrimSpace(stderr.String())))
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		message := fmt.Sprintf(DeclarativeShellErr, tool.name, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, message, err)
+	}
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeRunErr, tool.name, err), err)
+	}
+	return outputResult(stdout.Bytes())
+}
+
+func (a *httpAction) run(ctx context.Context, tool *Tool, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Send the HTTP request to the local endpoint
+	requestURL, appErr := render(tool, a.url, params)
+	if appErr != nil {
+		return nil, appErr
+	}
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeRenderErr, tool.name, err), err)
+	}
+	if !isLocalHost(parsedURL.Hostname()) {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeNonLocalURLErr, tool.name, parsedURL.Hostname()), nil)
+	}
+
+	body, appErr := render(tool, a.body, params)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	request, err := http.NewRequestWithContext(ctx, a.method, parsedURL.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeRunErr, tool.name, err), err)
+	}
+	for key, valueTemplate := range a.headers {
+		value, appErr := render(tool, valueTemplate, params)
+		if appErr != nil {
+			return nil, appErr
+		}
+		request.Header.Set(key, value)
+	}
+
+	response, err := a.client.Do(request)
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeRunErr, tool.name, err), err)
+	}
+	defer response.Body.Close()
+
+	output, err := io.ReadAll(io.LimitReader(response.Body, DeclarativeMaxOutputLength))
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeRunErr, tool.name, err), err)
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		message := fmt.Sprintf(DeclarativeHTTPStatusErr, tool.name, response.StatusCode, strings.TrimSpace(string(output)))
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, message, nil)
+	}
+	return outputResult(output)
+}
+
+func (a *textAction) run(ctx context.Context, tool *Tool, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Return the fixed text response
+	text, appErr := render(tool, a.text, params)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return outputResult([]byte(text))
+}
+
+func parseParameters(schema map[string]interface{}) (functions.ToolParametersMetadata, error) {
+	// Convert the YAML parameters schema to tool parameters
+	parameters := functions.ToolParametersMetadata{}
+	if schema != nil {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return parameters, err
+		}
+		if err := json.Unmarshal(data, &parameters); err != nil {
+			return parameters, err
+		}
+	}
+
+	if parameters.Type == "" {
+		parameters.Type = functions.FunctionObjectTypeText
+	}
+	if parameters.Properties == nil {
+		parameters.Properties = map[string]interface{}{}
+	}
+	return parameters, nil
+}
+
+func parseTemplate(name string, field string, text string) (*template.Template, error) {
+	// Parse a tool template, undeclared arguments fail the render
+	parsed, err := template.New(field).Funcs(template.FuncMap{"json": jsonValue}).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf(DeclarativeInvalidTemplateErr, field, name, err)
+	}
+	return parsed, nil
+}
+
+func parseURLTemplate(name string, text string) (*template.Template, error) {
+	// Parse a URL template, every argument is escaped for the path or the query it is rendered in
+	funcs := template.FuncMap{DeclarativePathEscapeFunc: pathEscape, DeclarativeQueryEscapeFunc: queryEscape}
+	parsed, err := template.New("url").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf(DeclarativeInvalidTemplateErr, "url", name, err)
+	}
+
+	escaping := &urlEscaping{}
+	escaping.escapeActions(parsed.Tree.Root)
+	return parsed, nil
+}
+
+func (escaping *urlEscaping) escapeActions(node parse.Node) {
+	// Walk the template in order, piping every action into the escape function of its URL part
+	switch typed := node.(type) {
+	case *parse.ListNode:
+		if typed == nil {
+			return
+		}
+		for _, child := range typed.Nodes {
+			escaping.escapeActions(child)
+		}
+	case *parse.TextNode:
+		escaping.query = escaping.query || bytes.ContainsRune(typed.Text, '?')
+	case *parse.ActionNode:
+		if typed.Pipe == nil || len(typed.Pipe.Decl) > 0 {
+			return
+		}
+		escapeFunc := DeclarativePathEscapeFunc
+		if escaping.query {
+			escapeFunc = DeclarativeQueryEscapeFunc
+		}
+		identifier := parse.NewIdentifier(escapeFunc).SetTree(nil).SetPos(typed.Pos)
+		command := &parse.CommandNode{NodeType: parse.NodeCommand, Pos: typed.Pos, Args: []parse.Node{identifier}}
+		typed.Pipe.Cmds = append(typed.Pipe.Cmds, command)
+	case *parse.IfNode:
+		escaping.escapeActionsInBranch(&typed.BranchNode)
+	case *parse.RangeNode:
+		escaping.escapeActionsInBranch(&typed.BranchNode)
+	case *parse.WithNode:
+		escaping.escapeActionsInBranch(&typed.BranchNode)
+	}
+}
+
+func (escaping *urlEscaping) escapeActionsInBranch(branch *parse.BranchNode) {
+	// Walk both lists of a branch
+	escaping.escapeActions(branch.List)
+	escaping.escapeActions(branch.ElseList)
+}
+
+func parseShellTemplate(name string, text string) (*template.Template, error) {
+	// Parse a shell command template, without the json function since only shell quoted arguments are safe
+	parsed, err := template.New("shell").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf(DeclarativeInvalidTemplateErr, "shell", name, err)
+	}
+
+	quoting := &shellQuoting{}
+	if action := quoting.findQuotedAction(parsed.Tree.Root); action != nil {
+		return nil, fmt.Errorf(DeclarativeQuotedArgumentErr, name, action.Pipe)
+	}
+	return parsed, nil
+}
+
+func (quoting *shellQuoting) findQuotedAction(node parse.Node) *parse.ActionNode {
+	// Walk the template in order, returning the first action rendered inside quotes or backticks
+	switch typed := node.(type) {
+	case *parse.ListNode:
+		if typed == nil {
+			return nil
+		}
+		for _, child := range typed.Nodes {
+			if action := quoting.findQuotedAction(child); action != nil {
+				return action
+			}
+		}
+	case *parse.TextNode:
+		quoting.scan(typed.Text)
+	case *parse.ActionNode:
+		if quoting.isQuoted() && typed.Pipe != nil && len(typed.Pipe.Decl) == 0 {
+			return typed
+		}
+	case *parse.IfNode:
+		return quoting.findQuotedActionInBranch(&typed.BranchNode)
+	case *parse.RangeNode:
+		return quoting.findQuotedActionInBranch(&typed.BranchNode)
+	case *parse.WithNode:
+		return quoting.findQuotedActionInBranch(&typed.BranchNode)
+	}
+	return nil
+}
+
+func (quoting *shellQuoting) findQuotedActionInBranch(branch *parse.BranchNode) *parse.ActionNode {
+	// Walk both lists of a branch
+	if action := quoting.findQuotedAction(branch.List); action != nil {
+		return action
+	}
+	return quoting.findQuotedAction(branch.ElseList)
+}
+
+func (quoting *shellQuoting) scan(text []byte) {
+	// Track the quotes opened and closed by literal shell text
+	for i := 0; i < len(text); i++ {
+		switch {
+		case quoting.single:
+			quoting.single = text[i] != '\''
+		case text[i] == '\\':
+			i++
+		case text[i] == '"':
+			quoting.double = !quoting.double
+		case text[i] == '`':
+			quoting.backtick = !quoting.backtick
+		case text[i] == '\'' && !quoting.double:
+			quoting.single = true
+		}
+	}
+}
+
+func (quoting *shellQuoting) isQuoted() bool {
+	// Return if text rendered now ends up inside quotes or backticks
+	return quoting.single || quoting.double || quoting.backtick
+}
+
+func templateData(tool *Tool, params functions.FunctionParams) map[string]interface{} {
+	// Return the call arguments, with missing parameters set to empty strings
+	data := make(map[string]interface{}, len(tool.parameters.Properties))
+	for name := range tool.parameters.Properties {
+		data[name] = ""
+	}
+	for key, value := range params {
+		data[key] = value
+	}
+	return data
+}
+
+func render(tool *Tool, tmpl *template.Template, data interface{}) (string, *errorhandler.AppError) {
+	// Render a tool template with the call arguments
+	if params, ok := data.(functions.FunctionParams); ok {
+		data = templateData(tool, params)
+	}
+
+	var output strings.Builder
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(DeclarativeRenderErr, tool.name, err), err)
+	}
+	return output.String(), nil
+}
+
+func outputResult(output []byte) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Return JSON output as is, any other output as text
+	output = bytes.TrimSpace(output)
+	if len(output) > 0 && json.Valid(output) {
+		return functions.NewResult(json.RawMessage(output), ""), nil
+	}
+	return functions.NewResult(string(output), ""), nil
+}
+
+func valueText(value interface{}) string {
+	// Return strings as is and any other argument as JSON
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func jsonValue(value interface{}) (string, error) {
+	// Template function encoding a value as JSON
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+func pathEscape(value interface{}) string {
+	// Template function escaping an argument as a URL path segment
+	return url.PathEscape(valueText(value))
+}
+
+func queryEscape(value interface{}) string {
+	// Template function escaping an argument as a URL query component
+	return url.QueryEscape(valueText(value))
+}
+
+func shellQuote(text string) string {
+	// Quote text as a single shell word
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
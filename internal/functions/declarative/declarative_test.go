@@ -0,0 +1,47 @@
+package declarative
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/functions"
+	"testing"
+	"time"
+)
+
+func TestHTTPURLArgumentsAreEscaped(t *testing.T) {
+	// Arguments can't change the path or the query of the URL they are rendered in
+	tests := []struct {
+		name   string
+		url    string
+		params functions.FunctionParams
+		want   string
+	}{
+		{"query value", "http://localhost:8080/search?q={{.q}}", functions.FunctionParams{"q": "a&admin=1"}, "http://localhost:8080/search?q=a%26admin%3D1"},
+		{"query space", "http://localhost:8080/search?q={{.q}}", functions.FunctionParams{"q": "a b"}, "http://localhost:8080/search?q=a+b"},
+		{"path segment", "http://localhost:8080/tickets/{{.id}}", functions.FunctionParams{"id": "../admin?x=1"}, "http://localhost:8080/tickets/..%2Fadmin%3Fx=1"},
+		{"path and query", "http://localhost:8080/{{.id}}/items?tag={{.id}}", functions.FunctionParams{"id": "a/b"}, "http://localhost:8080/a%2Fb/items?tag=a%2Fb"},
+		{"number", "http://localhost:8080/items?limit={{.limit}}", functions.FunctionParams{"limit": 5.0}, "http://localhost:8080/items?limit=5"},
+		{"branch", "http://localhost:8080/items{{if .q}}?q={{.q}}{{end}}", functions.FunctionParams{"q": "x#y"}, "http://localhost:8080/items?q=x%23y"},
+		{"variable", "http://localhost:8080/{{$id := .id}}{{$id}}", functions.FunctionParams{"id": "a/b"}, "http://localhost:8080/a%2Fb"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			definition := config.CustomToolConfig{
+				Description: "test tool",
+				Parameters:  map[string]interface{}{"type": "object", "properties": map[string]interface{}{"q": map[string]interface{}{"type": "string"}}},
+				HTTP:        &config.CustomToolHTTPConfig{URL: test.url},
+			}
+			tool, err := NewTool("test", definition, time.Second)
+			if err != nil {
+				t.Fatalf("NewTool() error = %v", err)
+			}
+			got, appErr := render(tool, tool.action.(*httpAction).url, test.params)
+			if appErr != nil {
+				t.Fatalf("render() error = %v", appErr.Message)
+			}
+			if got != test.want {
+				t.Errorf("render(%q) = %q, want %q", test.url, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package declarative
+
+import (
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+type Tool struct {
+	// Tool defined in the config file
+	name        string
+	description string
+	parameters  functions.ToolParametersMetadata
+	timeout     time.Duration
+	action      action
+}
+
+type action interface {
+	// Tool action run with the call arguments
+	run(ctx context.Context, tool *Tool, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError)
+}
+
+type shellAction struct {
+	// Shell command template, arguments are shell quoted
+	command *template.Template
+}
+
+type httpAction struct {
+	// HTTP request template against a local endpoint
+	client  *http.Client
+	method  string
+	url     *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+}
+
+type textAction struct {
+	// Fixed text response template
+	text *template.Template
+}
+
+type urlEscaping struct {
+	// Whether the literal text of a URL template already started the query
+	query bool
+}
+
+type shellQuoting struct {
+	// Quotes left open by the literal text of a shell template
+	single   bool
+	double   bool
+	backtick bool
+}
@@ -1,9 +1,41 @@
 package handler
 
+import "errors"
+
 const (
 	// Errors
 	FunctionFailedToRegisterErr = "function failed to register: %v"
 	FunctionDoesntExistsErr  = "function doesn't exist: %v"
 	FunctionEmptyNameErr    = "function name cannot be empty"
 	FunctionAlreadyExistsErr = "function already exists: %v"
+	FunctionUnknownEnabledErr = "enabled tool doesn't exist: %v"
+	FunctionNullArgumentsErr = "arguments are null"
+	FunctionPanickedErr = "function %s panicked: %v"
+	FunctionTimedOutErr = "function %s timed out after %s"
+	FunctionCancelledErr = "function %s was cancelled"
+	FunctionDeniedErr = "function %s denied: %s"
+)
+
+const (
+	// Log messages
+	FunctionPanicStackMsg = "Function %s panicked:\n%s"
+	FunctionAbandonedMsg = "Function %s abandoned: %v"
 )
+
+const (
+	// Denial reasons
+	FunctionDeniedByPolicyText = "the tool policy is deny"
+	FunctionDeniedByUserText = "the user declined the call"
+	FunctionDeniedUnattendedText = "the tool needs approval, run with -yes to allow it"
+	FunctionDeniedUnsafeText = "the tool needs approval and -deny-unsafe is set"
+)
+
+const (
+	// Approval decisions
+	ApprovalYes ApprovalDecision = "yes"
+	ApprovalNo ApprovalDecision = "no"
+	ApprovalAlways ApprovalDecision = "always"
+)
+
+// Denied function call error
+var ErrFunctionDenied = errors.New("function call denied")
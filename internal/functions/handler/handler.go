@@ -1,18 +1,39 @@
 package handler
 
 import (
-	"RTGPTGoCLI/internal/common"
 	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/functions/declarative"
 	"RTGPTGoCLI/internal/functions/math"
+	"RTGPTGoCLI/internal/functions/plugin"
+	"RTGPTGoCLI/internal/mcp"
 	"RTGPTGoCLI/pkg/errorhandler"
+	"RTGPTGoCLI/pkg/logger"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
-func NewHandler() *FunctionHandler {
+func NewHandler(cfg *config.Config) *FunctionHandler {
 	return &FunctionHandler{
 		functions: make(FunctionsType),
+		enabledTools: cfg.Tools,
+		toolsDir: cfg.ToolsDir,
+		toolTimeout: time.Duration(cfg.ToolTimeout) * time.Second,
+		toolTimeouts: cfg.ToolTimeouts,
+		toolPolicy: cfg.ToolPolicy,
+		toolPolicies: cfg.ToolPolicies,
+		approveAll: cfg.Yes,
+		denyUnsafe: cfg.DenyUnsafe,
+		alwaysApproved: make(map[string]bool),
+		mcpServers: cfg.MCPServers,
+		customTools: cfg.CustomTools,
 	}
 }
 
@@ -20,18 +41,92 @@ func (fh *FunctionHandler) LoadFunctions() *errorhandler.AppError {
 	// Load all custom functions to handler
 
 	functionsToLoad := []functions.FunctionInterface{
-		&math.FunctionMultiply{},
+		math.NewFunctionMultiply(),
 		// Add more functions here as you create them
 	}
+	builtInCount := len(functionsToLoad)
 
-	for _, fn := range functionsToLoad {
+	plugins, pluginErrs := plugin.LoadPlugins(fh.toolsDir, fh.toolTimeout)
+	for _, appErr := range pluginErrs {
+		logger.Warning(appErr.Message)
+	}
+	for _, pluginFn := range plugins {
+		functionsToLoad = append(functionsToLoad, pluginFn)
+	}
+
+	customTools, customToolErrs := declarative.LoadTools(fh.customTools, fh.toolTimeout)
+	for _, appErr := range customToolErrs {
+		logger.Warning(appErr.Message)
+	}
+	for _, customTool := range customTools {
+		functionsToLoad = append(functionsToLoad, customTool)
+	}
+	functionsToLoad = append(functionsToLoad, fh.loadMCPTools()...)
+
+	for i, fn := range functionsToLoad {
+		if !fh.isEnabled(fn.GetMetadata().Name) {
+			continue
+		}
 		if appErr := fh.registerFunction(fn); appErr != nil {
-			return appErr
+			// Built-in functions must register, external tools are skipped with a warning
+			if i < builtInCount {
+				return appErr
+			}
+			logger.Warning(appErr.Message)
+		}
+	}
+
+	for _, name := range fh.enabledTools {
+		if _, exists := fh.functions[name]; !exists {
+			return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionUnknownEnabledErr, name), nil)
 		}
 	}
 	return nil
 }
 
+func (fh *FunctionHandler) loadMCPTools() []functions.FunctionInterface {
+	// Start the configured MCP servers and wrap their tools as functions
+	names := make([]string, 0, len(fh.mcpServers))
+	for name := range fh.mcpServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := []functions.FunctionInterface{}
+	for _, name := range names {
+		client := mcp.NewClient(name, fh.mcpServers[name], fh.toolTimeout)
+		if err := client.Start(context.Background()); err != nil {
+			logger.Warning(fmt.Sprintf(mcp.MCPStartServerErr, name, err))
+			continue
+		}
+		fh.mcpClients = append(fh.mcpClients, client)
+
+		definitions, err := client.ListTools(context.Background())
+		if err != nil {
+			logger.Warning(fmt.Sprintf(mcp.MCPListToolsErr, name, err))
+			continue
+		}
+		logger.Debug(fmt.Sprintf(mcp.MCPServerToolsMsg, name, len(definitions)))
+		for _, tool := range mcp.NewTools(client, definitions) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+func (fh *FunctionHandler) Close() {
+	// Stop the started MCP servers
+	for _, client := range fh.mcpClients {
+		client.Close()
+	}
+	fh.mcpClients = nil
+}
+
+func (fh *FunctionHandler) isEnabled(name string) bool {
+	// Return if a function is enabled, an empty list enables every function
+	return len(fh.enabledTools) == 0 || slices.Contains(fh.enabledTools, name)
+}
+
 func (fh *FunctionHandler) GenerateOpenAITools() []interface{} {
 	// Generate OpenAI tools for session configuration
 	tools := make([]interface{}, 0, len(fh.functions))
@@ -41,20 +136,190 @@ func (fh *FunctionHandler) GenerateOpenAITools() []interface{} {
 	return tools
 }
 
-func (fh *FunctionHandler) Execute(ctx context.Context, name string, argumentsJSON string) (interface{}, *errorhandler.AppError) {
+func (fh *FunctionHandler) GetTools() []functions.OpenAIToolsPayload {
+	// Get the registered functions as OpenAI tools, sorted by name
+	tools := make([]functions.OpenAIToolsPayload, 0, len(fh.functions))
+	for _, fn := range fh.functions {
+		tools = append(tools, fn.ConvertToOpenAITool())
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+	return tools
+}
+
+func (fh *FunctionHandler) Execute(ctx context.Context, name string, argumentsJSON string) (*functions.FunctionResult, *errorhandler.AppError) {
 	// Execute function
+	call, appErr := fh.Prepare(ctx, name, argumentsJSON)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return call.Run(ctx)
+}
+
+func (fh *FunctionHandler) Prepare(ctx context.Context, name string, argumentsJSON string) (*PreparedCall, *errorhandler.AppError) {
+	// Parse, validate and authorize a function call, without running it
 	fn, err := fh.getFunction(name)
 	if err != nil {
 		return nil, err
 	}
 
-	var params functions.FunctionParams
-	if err := json.Unmarshal([]byte(argumentsJSON), &params); err != nil {
-		return nil, common.NewErrJsonUnmarshalAppError(err)
+	params, appErr := parseArguments(name, argumentsJSON)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if argsErr := functions.ValidateParams(name, fn.ConvertToOpenAITool().Parameters, params); argsErr != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, argsErr.Error(), argsErr)
 	}
 
-	result, appErr := fn.Execute(ctx, params)
-	return result, appErr
+	if appErr := fh.authorize(ctx, name, params); appErr != nil {
+		return nil, appErr
+	}
+
+	return &PreparedCall{handler: fh, name: name, fn: fn, params: params}, nil
+}
+
+func (call *PreparedCall) Run(ctx context.Context) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Run the prepared call bounded by its timeout
+	return call.handler.executeWithTimeout(ctx, call.name, call.fn, call.params)
+}
+
+func (fh *FunctionHandler) SetApprover(approver Approver) {
+	// Set who is asked to approve the calls of "ask" tools, without one they are denied
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	fh.approver = approver
+}
+
+func (fh *FunctionHandler) GetPolicy(name string) string {
+	// Return the policy of a tool, its own policy or the default one
+	if policy, exists := fh.toolPolicies[name]; exists {
+		return policy
+	}
+	return fh.toolPolicy
+}
+
+func (fh *FunctionHandler) authorize(ctx context.Context, name string, params functions.FunctionParams) *errorhandler.AppError {
+	// Check the tool policy, asking the approver for "ask" tools not approved for the whole session
+	switch fh.GetPolicy(name) {
+	case config.SafeToolPolicy:
+		return nil
+	case config.DenyToolPolicy:
+		return deniedError(name, FunctionDeniedByPolicyText)
+	}
+
+	fh.mu.Lock()
+	approver := fh.approver
+	alwaysApproved := fh.alwaysApproved[name]
+	fh.mu.Unlock()
+
+	switch {
+	case fh.approveAll || alwaysApproved:
+		return nil
+	case fh.denyUnsafe:
+		return deniedError(name, FunctionDeniedUnsafeText)
+	case approver == nil:
+		return deniedError(name, FunctionDeniedUnattendedText)
+	}
+
+	decision, err := approver.ApproveFunctionCall(ctx, name, params)
+	if err != nil {
+		return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionCancelledErr, name), err)
+	}
+
+	switch decision {
+	case ApprovalAlways:
+		fh.mu.Lock()
+		fh.alwaysApproved[name] = true
+		fh.mu.Unlock()
+		return nil
+	case ApprovalYes:
+		return nil
+	}
+	return deniedError(name, FunctionDeniedByUserText)
+}
+
+func deniedError(name string, reason string) *errorhandler.AppError {
+	// Build the error of a denied call, answered to the model as a denial
+	return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionDeniedErr, name, reason), ErrFunctionDenied)
+}
+
+func (fh *FunctionHandler) executeWithTimeout(ctx context.Context, name string, fn functions.FunctionInterface, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Execute function in its own goroutine bounded by its timeout, returning as soon as the call is cancelled
+	timeout := fh.timeoutFor(name, fn)
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan executionResult, 1)
+	go func() {
+		result, appErr := fh.executeFunction(callCtx, name, fn, params)
+		done <- executionResult{result: result, appErr: appErr}
+	}()
+
+	select {
+	case execution := <-done:
+		if execution.appErr != nil && callCtx.Err() != nil {
+			return nil, contextError(name, timeout, callCtx.Err())
+		}
+		return execution.result, execution.appErr
+	case <-callCtx.Done():
+		logger.Debug(fmt.Sprintf(FunctionAbandonedMsg, name, callCtx.Err()))
+		return nil, contextError(name, timeout, callCtx.Err())
+	}
+}
+
+func (fh *FunctionHandler) timeoutFor(name string, fn functions.FunctionInterface) time.Duration {
+	// Return the call timeout of a function: config file override, then the function's own, then the global timeout
+	if seconds, exists := fh.toolTimeouts[name]; exists {
+		return time.Duration(seconds) * time.Second
+	}
+	if timed, ok := fn.(functions.TimeoutFunction); ok && timed.Timeout() > 0 {
+		return timed.Timeout()
+	}
+	return fh.toolTimeout
+}
+
+func contextError(name string, timeout time.Duration, err error) *errorhandler.AppError {
+	// Convert the end of a call context into a timeout or cancellation error
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionTimedOutErr, name, timeout), err)
+	}
+	return errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionCancelledErr, name), err)
+}
+
+func (fh *FunctionHandler) executeFunction(ctx context.Context, name string, fn functions.FunctionInterface, params functions.FunctionParams) (result *functions.FunctionResult, appErr *errorhandler.AppError) {
+	// Execute function, recovering its panics as call errors
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Debug(fmt.Sprintf(FunctionPanicStackMsg, name, debug.Stack()))
+			err := fmt.Errorf("%v", recovered)
+			result = nil
+			appErr = errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionPanickedErr, name, err), err)
+		}
+	}()
+
+	return fn.Execute(ctx, params)
+}
+
+func parseArguments(name string, argumentsJSON string) (functions.FunctionParams, *errorhandler.AppError) {
+	// Parse the arguments JSON object, empty arguments are an empty object
+	params := functions.FunctionParams{}
+	if strings.TrimSpace(argumentsJSON) == "" {
+		return params, nil
+	}
+
+	if err := json.Unmarshal([]byte(argumentsJSON), &params); err != nil || params == nil {
+		if err == nil {
+			err = fmt.Errorf(FunctionNullArgumentsErr)
+		}
+		argsErr := functions.NewArgumentsError(name, []functions.ValidationIssue{{
+			Path:    functions.ValidationRootPath,
+			Message: fmt.Sprintf(functions.FunctionArgumentsNotJSONErr, err),
+		}})
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, argsErr.Error(), argsErr)
+	}
+	return params, nil
 }
 
 func (fh *FunctionHandler) getFunction(name string) (functions.FunctionInterface, *errorhandler.AppError) {
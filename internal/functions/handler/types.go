@@ -1,6 +1,14 @@
 package handler
 
-import "RTGPTGoCLI/internal/functions"
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/internal/mcp"
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"sync"
+	"time"
+)
 
 // Functions type
 type FunctionsType map[string]functions.FunctionInterface
@@ -8,4 +16,41 @@ type FunctionsType map[string]functions.FunctionInterface
 type FunctionHandler struct {
 	// Function handler struct
 	functions FunctionsType
+	enabledTools []string
+	toolsDir string
+	toolTimeout time.Duration
+	toolTimeouts map[string]int
+	toolPolicy string
+	toolPolicies map[string]string
+	approveAll bool
+	denyUnsafe bool
+
+	mu sync.Mutex
+	approver Approver
+	alwaysApproved map[string]bool
+	mcpServers map[string]config.MCPServerConfig
+	mcpClients []*mcp.Client
+	customTools map[string]config.CustomToolConfig
+}
+
+type PreparedCall struct {
+	// Validated and authorized function call, ready to run
+	handler *FunctionHandler
+	name    string
+	fn      functions.FunctionInterface
+	params  functions.FunctionParams
+}
+
+type executionResult struct {
+	// Outcome of a function call run in its own goroutine
+	result *functions.FunctionResult
+	appErr *errorhandler.AppError
+}
+
+// Approval decision type
+type ApprovalDecision string
+
+type Approver interface {
+	// Asks the user to approve a call of an "ask" tool
+	ApproveFunctionCall(ctx context.Context, name string, params functions.FunctionParams) (ApprovalDecision, error)
 }
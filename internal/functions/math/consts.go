@@ -10,5 +10,6 @@ const (
 	// Multiply custom function constants
 	MultiplyFunctionNameText = "multiply"
 	MultiplyFunctionDescriptionText = "Multiply multiple numbers together"
-	MultiplyArrayDescriptionText = "Array of numbers to multiply together"
+	MultiplySummaryText = "%s = %s"
+	MultiplySeparatorText = " * "
 )
@@ -6,65 +6,37 @@ import (
 	"RTGPTGoCLI/pkg/logger"
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
-func (mtpFn *FunctionMultiply) Execute(ctx context.Context, params functions.FunctionParams) (interface{}, *errorhandler.AppError) {
+func NewFunctionMultiply() functions.FunctionInterface {
+	// Create custom multiply function
+	return functions.NewStructFunction[FunctionMultiply](MultiplyFunctionNameText, MultiplyFunctionDescriptionText)
+}
+
+func (mtpFn *FunctionMultiply) Run(ctx context.Context) (*functions.FunctionResult, *errorhandler.AppError) {
 	// Execute custom multiply function
-	logger.Debug(fmt.Sprintf(ExecutingMultiplyWithParams, params))
-	
-	numbers, err := GetNumbersFromParams(params)
-	if err != nil {
-		return nil, err
-	}
+	logger.Debug(fmt.Sprintf(ExecutingMultiplyWithParams, mtpFn.Numbers))
 
-	if len(numbers) < 2 {
+	if len(mtpFn.Numbers) < 2 {
 		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, AtLeastTwoNumbersRequiredErr, nil)
 	}
 
-	result := mtpFn.calculateResult(numbers)
-	return functions.FunctionResponse{
+	result := mtpFn.calculateResult(mtpFn.Numbers)
+	return functions.NewResult(MultiplyResult{
 		Result: result,
-		Operation: functions.FunctionOperationText,
-		Inputs: numbers,
-	}, nil
-}
-
-func (mtpFn *FunctionMultiply) GetMetadata() functions.FunctionPayload {
-	// Get function metadata
-	return functions.FunctionPayload{
-		Name:        MultiplyFunctionNameText,
-		Description: MultiplyFunctionDescriptionText,
-		Parameters: []functions.FunctionParameterMetadata{
-			{
-				Name:        functions.FunctionNumbersParamText,
-				Type:        functions.FunctionArrayTypeText,
-				Description: MultiplyArrayDescriptionText,
-				Required:    functions.FunctionRequiredValue,
-			},
-		},
-	}
+		Inputs: mtpFn.Numbers,
+	}, mtpFn.summary(result)), nil
 }
 
-func (mtpFn *FunctionMultiply) ConvertToOpenAITool() functions.OpenAIToolsPayload {
-	// Convert function to OpenAI tool
-	return functions.OpenAIToolsPayload{
-		Type: functions.FunctionTypeText,
-		Name: MultiplyFunctionNameText,
-		Description: MultiplyFunctionDescriptionText,
-		Parameters: functions.ToolParametersMetadata{
-			Type: functions.FunctionObjectTypeText,
-			Properties: map[string]interface{}{
-				functions.FunctionNumbersParamText: MultiplyParametersProperties{
-					Type:        functions.FunctionArrayTypeText,
-					Description: MultiplyArrayDescriptionText,
-					Items: MultiplyPropertiesItem{
-						Type: functions.FunctionNumberTypeText,
-					},
-				},
-			},
-			Required: []string{functions.FunctionNumbersParamText},
-		},
+func (mtpFn *FunctionMultiply) summary(result float64) string {
+	// Summarize the multiplication, e.g. 2 * 3.5 = 7
+	factors := make([]string, len(mtpFn.Numbers))
+	for i, num := range mtpFn.Numbers {
+		factors[i] = strconv.FormatFloat(num, 'g', -1, 64)
 	}
+	return fmt.Sprintf(MultiplySummaryText, strings.Join(factors, MultiplySeparatorText), strconv.FormatFloat(result, 'g', -1, 64))
 }
 
 func (mtpFn *FunctionMultiply) calculateResult(numbers []float64) float64 {
@@ -1,22 +1,12 @@
 package math
 
-// Custom multiply function struct
-type FunctionMultiply struct{}
-
-type MultiplyPayload struct {
-	// Multiply request struct
-	Numbers []float64 `json:"numbers"`
+type FunctionMultiply struct {
+	// Custom multiply function arguments
+	Numbers []float64 `json:"numbers" description:"Array of numbers to multiply together" required:"true" minItems:"2"`
 }
 
-type MultiplyParametersProperties struct {
-	// Multiply parameters properties
-	Type string `json:"type"`
-	Description string `json:"description"`
-	Items MultiplyPropertiesItem `json:"items"`
+type MultiplyResult struct {
+	// Custom multiply function result data
+	Result float64   `json:"result"`
+	Inputs []float64 `json:"inputs"`
 }
-
-type MultiplyPropertiesItem struct {
-	// property item metadata
-	Type string `json:"type"`
-}	
-	
\ No newline at end of file
@@ -0,0 +1,39 @@
+package plugin
+
+import "time"
+
+const (
+	// Plugin protocol constants
+	PluginDescribeArg = "--describe"
+	PluginDescribeTimeout = 5 * time.Second
+	PluginMaxStderrLength = 2000
+	PluginWaitDelay = time.Second
+)
+
+const (
+	// Log messages
+	PluginLoadedMsg = "Loaded tool plugin %s from %s"
+	PluginStderrMsg = "Tool plugin %s stderr: %s"
+	PluginExecutingMsg = "Executing tool plugin %s with params: %+v"
+)
+
+const (
+	// Errors
+	PluginReadDirErr = "failed to read tools directory %s: %v"
+	PluginDescribeErr = "failed to describe tool plugin %s: %v"
+	PluginInvalidDescriptionErr = "invalid description of tool plugin %s: %v"
+	PluginMissingNameErr = "tool plugin %s has no name"
+	PluginExitErr = "tool %s %s (exit code %d): %s"
+	PluginRunErr = "failed to run tool %s: %v"
+	PluginInvalidOutputErr = "tool %s returned invalid JSON: %v"
+)
+
+// Errors reported by plugins, by exit code
+var PluginExitCodeErrors = map[int]string{
+	2:   "rejected its arguments",
+	126: "is not executable",
+	127: "could not find its command",
+}
+
+// Error reported by plugins for any other exit code
+const PluginDefaultExitErr = "failed"
@@ -0,0 +1,188 @@
+package plugin
+
+import (
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"RTGPTGoCLI/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func LoadPlugins(dir string, defaultTimeout time.Duration) ([]*Plugin, []*errorhandler.AppError) {
+	// Load every executable of the tools directory as a plugin
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []*errorhandler.AppError{
+			errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginReadDirErr, dir, err), err),
+		}
+	}
+
+	plugins := []*Plugin{}
+	appErrs := []*errorhandler.AppError{}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !isExecutable(path) {
+			continue
+		}
+
+		plugin, appErr := NewPlugin(path, defaultTimeout)
+		if appErr != nil {
+			appErrs = append(appErrs, appErr)
+			continue
+		}
+		logger.Debug(fmt.Sprintf(PluginLoadedMsg, plugin.description.Name, path))
+		plugins = append(plugins, plugin)
+	}
+	return plugins, appErrs
+}
+
+func NewPlugin(path string, defaultTimeout time.Duration) (*Plugin, *errorhandler.AppError) {
+	// Create plugin from the description printed by the executable
+	ctx, cancel := context.WithTimeout(context.Background(), PluginDescribeTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, PluginDescribeArg)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginDescribeErr, path, describeError(err, &stderr)), err)
+	}
+
+	var description Description
+	if err := json.Unmarshal(stdout.Bytes(), &description); err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginInvalidDescriptionErr, path, err), err)
+	}
+	if description.Name == "" {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginMissingNameErr, path), nil)
+	}
+	if description.Parameters.Type == "" {
+		description.Parameters.Type = functions.FunctionObjectTypeText
+	}
+	if description.Parameters.Properties == nil {
+		description.Parameters.Properties = map[string]interface{}{}
+	}
+
+	timeout := defaultTimeout
+	if description.TimeoutSeconds > 0 {
+		timeout = time.Duration(description.TimeoutSeconds) * time.Second
+	}
+
+	return &Plugin{
+		path:        path,
+		description: description,
+		timeout:     timeout,
+	}, nil
+}
+
+func (p *Plugin) Execute(ctx context.Context, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Run the plugin with the arguments as JSON on stdin, reading its JSON result from stdout
+	name := p.description.Name
+	logger.Debug(fmt.Sprintf(PluginExecutingMsg, name, params))
+
+	input, err := json.Marshal(params)
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginRunErr, name, err), err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = PluginWaitDelay
+	runErr := cmd.Run()
+
+	if stderr.Len() > 0 {
+		logger.Debug(fmt.Sprintf(PluginStderrMsg, name, truncate(stderr.String())))
+	}
+
+	if ctx.Err() != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginRunErr, name, ctx.Err()), ctx.Err())
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, exitErrorMessage(name, exitErr.ExitCode(), &stderr), runErr)
+	}
+	if runErr != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginRunErr, name, runErr), runErr)
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	if !json.Valid(output) {
+		err := errors.New(truncate(string(output)))
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(PluginInvalidOutputErr, name, err), err)
+	}
+	return functions.NewResult(json.RawMessage(output), ""), nil
+}
+
+func (p *Plugin) Timeout() time.Duration {
+	// Return the call timeout of the plugin
+	return p.timeout
+}
+
+func (p *Plugin) GetMetadata() functions.FunctionPayload {
+	// Get plugin metadata from its JSON schema parameters
+	return functions.FunctionPayload{
+		Name:        p.description.Name,
+		Description: p.description.Description,
+		Parameters:  functions.ParametersFromSchema(p.description.Parameters),
+	}
+}
+
+func (p *Plugin) ConvertToOpenAITool() functions.OpenAIToolsPayload {
+	// Convert plugin to OpenAI tool
+	return functions.OpenAIToolsPayload{
+		Type:        functions.FunctionTypeText,
+		Name:        p.description.Name,
+		Description: p.description.Description,
+		Parameters:  p.description.Parameters,
+	}
+}
+
+func exitErrorMessage(name string, exitCode int, stderr *bytes.Buffer) string {
+	// Map a plugin exit code to an error message
+	reason, exists := PluginExitCodeErrors[exitCode]
+	if !exists {
+		reason = PluginDefaultExitErr
+	}
+	return fmt.Sprintf(PluginExitErr, name, reason, exitCode, truncate(stderr.String()))
+}
+
+func describeError(err error, stderr *bytes.Buffer) error {
+	// Add the captured stderr to a describe error
+	if stderr.Len() == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: %s", err, truncate(stderr.String()))
+}
+
+func isExecutable(path string) bool {
+	// Return if a path is an executable regular file
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return info.Mode().Perm()&0111 != 0
+}
+
+func truncate(text string) string {
+	// Trim captured output to a readable length
+	text = strings.TrimSpace(text)
+	if len(text) > PluginMaxStderrLength {
+		return text[:PluginMaxStderrLength] + "..."
+	}
+	return text
+}
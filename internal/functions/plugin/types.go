@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"RTGPTGoCLI/internal/functions"
+	"time"
+)
+
+type Plugin struct {
+	// Executable tool plugin
+	path        string
+	description Description
+	timeout     time.Duration
+}
+
+type Description struct {
+	// Plugin description printed on --describe
+	Name           string                           `json:"name"`
+	Description    string                           `json:"description"`
+	Parameters     functions.ToolParametersMetadata `json:"parameters"`
+	TimeoutSeconds int                              `json:"timeout_seconds,omitempty"`
+}
@@ -0,0 +1,24 @@
+package functions
+
+import "encoding/json"
+
+func NewResult(data interface{}, summary string) *FunctionResult {
+	// Create function result
+	return &FunctionResult{
+		Data:    data,
+		Summary: summary,
+	}
+}
+
+func (result *FunctionResult) Output() (string, error) {
+	// Serialize the result to the output sent to the model
+	if result == nil {
+		result = &FunctionResult{}
+	}
+
+	output, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,261 @@
+package functions
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func SchemaFromStruct(argsType reflect.Type) (ToolParametersMetadata, error) {
+	// Build the JSON schema of an arguments struct from its field tags
+	for argsType.Kind() == reflect.Pointer {
+		argsType = argsType.Elem()
+	}
+	if argsType.Kind() != reflect.Struct {
+		return ToolParametersMetadata{}, fmt.Errorf(FunctionUnsupportedArgsTypeErr, argsType)
+	}
+
+	schema := objectSchema(argsType)
+	required, _ := schema[SchemaRequiredKey].([]string)
+	return ToolParametersMetadata{
+		Type:       FunctionObjectTypeText,
+		Properties: schema[SchemaPropertiesKey].(map[string]interface{}),
+		Required:   required,
+	}, nil
+}
+
+func DecodeParams(params FunctionParams, target interface{}) error {
+	// Decode call arguments into an arguments struct, with the same types the schema validation accepts
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf(FunctionUnsupportedArgsTypeErr, value.Type())
+	}
+	return decodeValue(map[string]interface{}(params), value.Elem(), "")
+}
+
+func objectSchema(structType reflect.Type) map[string]interface{} {
+	// Build the schema of a struct type
+	properties := map[string]interface{}{}
+	required := []string{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+
+		property := typeSchema(field.Type)
+		applyTags(property, field)
+		properties[name] = property
+
+		if field.Tag.Get(SchemaRequiredTag) == "true" {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		SchemaTypeKey:       FunctionObjectTypeText,
+		SchemaPropertiesKey: properties,
+	}
+	if len(required) > 0 {
+		schema[SchemaRequiredKey] = required
+	}
+	return schema
+}
+
+func typeSchema(fieldType reflect.Type) map[string]interface{} {
+	// Build the schema of a field type
+	switch fieldType.Kind() {
+	case reflect.Pointer:
+		return typeSchema(fieldType.Elem())
+	case reflect.String:
+		return map[string]interface{}{SchemaTypeKey: FunctionStringTypeText}
+	case reflect.Bool:
+		return map[string]interface{}{SchemaTypeKey: FunctionBooleanTypeText}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{SchemaTypeKey: FunctionIntegerTypeText}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{SchemaTypeKey: FunctionNumberTypeText}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			SchemaTypeKey:  FunctionArrayTypeText,
+			SchemaItemsKey: typeSchema(fieldType.Elem()),
+		}
+	case reflect.Struct:
+		return objectSchema(fieldType)
+	case reflect.Map:
+		return map[string]interface{}{SchemaTypeKey: FunctionObjectTypeText}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func applyTags(property map[string]interface{}, field reflect.StructField) {
+	// Add the schema keywords set by the field tags
+	if description := field.Tag.Get(SchemaDescriptionTag); description != "" {
+		property[SchemaDescriptionKey] = description
+	}
+
+	if enum := field.Tag.Get(SchemaEnumTag); enum != "" {
+		values := []interface{}{}
+		for _, entry := range strings.Split(enum, ",") {
+			values = append(values, tagValue(property[SchemaTypeKey], strings.TrimSpace(entry)))
+		}
+		property[SchemaEnumKey] = values
+	}
+
+	numericTags := map[string]string{
+		SchemaMinimumTag:  SchemaMinimumKey,
+		SchemaMaximumTag:  SchemaMaximumKey,
+		SchemaMinItemsTag: SchemaMinItemsKey,
+		SchemaMaxItemsTag: SchemaMaxItemsKey,
+	}
+	for tag, key := range numericTags {
+		if number, err := strconv.ParseFloat(field.Tag.Get(tag), 64); err == nil {
+			property[key] = number
+		}
+	}
+}
+
+func tagValue(schemaType interface{}, text string) interface{} {
+	// Convert an enum tag entry to the field type
+	switch schemaType {
+	case FunctionNumberTypeText, FunctionIntegerTypeText:
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case FunctionBooleanTypeText:
+		if boolean, err := strconv.ParseBool(text); err == nil {
+			return boolean
+		}
+	}
+	return text
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	// Return the JSON name of an exported field
+	if !field.IsExported() {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get(SchemaJSONTag), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
+
+func decodeValue(value interface{}, target reflect.Value, path string) error {
+	// Decode a JSON value into a typed target
+	if value == nil {
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeValue(value, target.Elem(), path)
+	case reflect.Interface:
+		target.Set(reflect.ValueOf(value))
+	case reflect.String:
+		text, ok := value.(string)
+		if !ok {
+			return invalidParameter(path, FunctionStringTypeText, value)
+		}
+		target.SetString(text)
+	case reflect.Bool:
+		boolean, ok := value.(bool)
+		if !ok {
+			return invalidParameter(path, FunctionBooleanTypeText, value)
+		}
+		target.SetBool(boolean)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := numberValue(value)
+		if !ok || number != float64(int64(number)) || target.OverflowInt(int64(number)) {
+			return invalidParameter(path, FunctionIntegerTypeText, value)
+		}
+		target.SetInt(int64(number))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := numberValue(value)
+		if !ok || number < 0 || number != float64(uint64(number)) || target.OverflowUint(uint64(number)) {
+			return invalidParameter(path, FunctionIntegerTypeText, value)
+		}
+		target.SetUint(uint64(number))
+	case reflect.Float32, reflect.Float64:
+		number, ok := numberValue(value)
+		if !ok {
+			return invalidParameter(path, FunctionNumberTypeText, value)
+		}
+		target.SetFloat(number)
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return invalidParameter(path, FunctionArrayTypeText, value)
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return invalidParameter(path, FunctionObjectTypeText, value)
+		}
+		mapValue := reflect.MakeMapWithSize(target.Type(), len(object))
+		for key, item := range object {
+			itemValue := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeValue(item, itemValue, joinPath(path, key)); err != nil {
+				return err
+			}
+			mapValue.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), itemValue)
+		}
+		target.Set(mapValue)
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return invalidParameter(path, FunctionObjectTypeText, value)
+		}
+		for i := 0; i < target.NumField(); i++ {
+			name, ok := fieldName(target.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if err := decodeValue(object[name], target.Field(i), joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf(FunctionUnsupportedArgsTypeErr, target.Type())
+	}
+	return nil
+}
+
+func numberValue(value interface{}) (float64, bool) {
+	// Return a JSON number as float
+	number, ok := value.(float64)
+	return number, ok
+}
+
+func invalidParameter(path string, expected string, value interface{}) error {
+	// Create an invalid parameter error
+	return fmt.Errorf(FunctionInvalidParameterErr, path, expected, value)
+}
+
+func joinPath(path string, name string) string {
+	// Join a parameter path and a field name
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
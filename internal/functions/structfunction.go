@@ -0,0 +1,56 @@
+package functions
+
+import (
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"fmt"
+	"reflect"
+)
+
+func NewStructFunction[T any, P ToolArgsPointer[T]](name string, description string) *StructFunction[T, P] {
+	// Create function from a typed arguments struct, its schema comes from the struct tags
+	parameters, err := SchemaFromStruct(reflect.TypeFor[T]())
+	if err != nil {
+		panic(err)
+	}
+
+	return &StructFunction[T, P]{
+		name:        name,
+		description: description,
+		parameters:  parameters,
+	}
+}
+
+func (sf *StructFunction[T, P]) Execute(ctx context.Context, params FunctionParams) (*FunctionResult, *errorhandler.AppError) {
+	// Decode the arguments into a new struct and run it
+	for _, name := range sf.parameters.Required {
+		if _, exists := params[name]; !exists {
+			return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(FunctionMissingRequiredParameterErr, name), nil)
+		}
+	}
+
+	args := P(new(T))
+	if err := DecodeParams(params, args); err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, err.Error(), err)
+	}
+	return args.Run(ctx)
+}
+
+func (sf *StructFunction[T, P]) GetMetadata() FunctionPayload {
+	// Get function metadata from the struct schema
+	return FunctionPayload{
+		Name:        sf.name,
+		Description: sf.description,
+		Parameters:  ParametersFromSchema(sf.parameters),
+	}
+}
+
+func (sf *StructFunction[T, P]) ConvertToOpenAITool() OpenAIToolsPayload {
+	// Convert function to OpenAI tool
+	return OpenAIToolsPayload{
+		Type:        FunctionTypeText,
+		Name:        sf.name,
+		Description: sf.description,
+		Parameters:  sf.parameters,
+	}
+}
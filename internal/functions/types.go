@@ -3,6 +3,7 @@ package functions
 import (
 	"RTGPTGoCLI/pkg/errorhandler"
 	"context"
+	"time"
 )
 
 // Function parameters type
@@ -10,11 +11,16 @@ type FunctionParams map[string]interface{}
 
 type FunctionInterface interface {
 	// Custom function execution
-	Execute(ctx context.Context, params FunctionParams) (interface{}, *errorhandler.AppError)
+	Execute(ctx context.Context, params FunctionParams) (*FunctionResult, *errorhandler.AppError)
 	GetMetadata() FunctionPayload
 	ConvertToOpenAITool() OpenAIToolsPayload
 }
 
+type TimeoutFunction interface {
+	// Function declaring its own call timeout, overriding the global tool timeout
+	Timeout() time.Duration
+}
+
 type OpenAIToolsPayload struct {
 	// OpenAI tool metadata
 	Type string `json:"type"`
@@ -46,8 +52,40 @@ type FunctionParameterMetadata struct {
 	Required    bool
 }
 
-type FunctionResponse struct {
-	Result  float64 `json:"result"`
-	Operation string `json:"operation"`
-	Inputs    []float64 `json:"inputs"`
+type FunctionResult struct {
+	// Function call result, any JSON data plus an optional human readable summary
+	Data    interface{} `json:"data"`
+	Summary string      `json:"summary,omitempty"`
+}
+
+type ToolArgs interface {
+	// Typed arguments of a struct function, running the function on themselves
+	Run(ctx context.Context) (*FunctionResult, *errorhandler.AppError)
+}
+
+type ToolArgsPointer[T any] interface {
+	// Pointer to typed arguments
+	*T
+	ToolArgs
+}
+
+type StructFunction[T any, P ToolArgsPointer[T]] struct {
+	// Function built from a typed arguments struct
+	name        string
+	description string
+	parameters  ToolParametersMetadata
+}
+
+type ArgumentsError struct {
+	// Function call arguments that don't match the function schema
+	Function string            `json:"-"`
+	Code     string            `json:"error"`
+	Hint     string            `json:"hint"`
+	Details  []ValidationIssue `json:"details"`
+}
+
+type ValidationIssue struct {
+	// Single argument validation failure
+	Path    string `json:"path"`
+	Message string `json:"message"`
 }
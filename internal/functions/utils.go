@@ -0,0 +1,29 @@
+package functions
+
+import (
+	"slices"
+	"sort"
+)
+
+func ParametersFromSchema(schema ToolParametersMetadata) []FunctionParameterMetadata {
+	// Convert the properties of a JSON schema to function parameters metadata
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]FunctionParameterMetadata, 0, len(names))
+	for _, name := range names {
+		property, _ := schema.Properties[name].(map[string]interface{})
+		paramType, _ := property["type"].(string)
+		description, _ := property["description"].(string)
+		parameters = append(parameters, FunctionParameterMetadata{
+			Name:        name,
+			Type:        paramType,
+			Description: description,
+			Required:    slices.Contains(schema.Required, name),
+		})
+	}
+	return parameters
+}
@@ -0,0 +1,176 @@
+package functions
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+func ValidateParams(function string, schema ToolParametersMetadata, params FunctionParams) error {
+	// Validate call arguments against the function JSON schema, a schema that can't be read fails every call
+	schemaMap, err := schemaToMap(schema)
+	if err != nil {
+		return fmt.Errorf(FunctionInvalidSchemaErr, function, err)
+	}
+
+	issues := validateValue(map[string]interface{}(params), schemaMap, ValidationRootPath)
+	if len(issues) == 0 {
+		return nil
+	}
+	return NewArgumentsError(function, issues)
+}
+
+func NewArgumentsError(function string, issues []ValidationIssue) *ArgumentsError {
+	// Create arguments error sent back to the model
+	return &ArgumentsError{
+		Function: function,
+		Code:     ValidationErrorCode,
+		Hint:     ValidationRetryHint,
+		Details:  issues,
+	}
+}
+
+func (argsErr *ArgumentsError) Error() string {
+	// Return the validation issues as a single line
+	messages := make([]string, 0, len(argsErr.Details))
+	for _, issue := range argsErr.Details {
+		messages = append(messages, issue.Path+" "+issue.Message)
+	}
+	return fmt.Sprintf(FunctionInvalidArgumentsErr, argsErr.Function, strings.Join(messages, "; "))
+}
+
+func (argsErr *ArgumentsError) Output() string {
+	// Return the structured function call output of the error
+	data, err := json.Marshal(argsErr)
+	if err != nil {
+		return argsErr.Error()
+	}
+	return string(data)
+}
+
+func schemaToMap(schema ToolParametersMetadata) (map[string]interface{}, error) {
+	// Normalize a schema, whose properties may be typed structs, to plain JSON values
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaMap map[string]interface{}
+	err = json.Unmarshal(data, &schemaMap)
+	return schemaMap, err
+}
+
+func validateValue(value interface{}, schema map[string]interface{}, path string) []ValidationIssue {
+	// Validate a JSON value against a schema
+	if schemaType, exists := schema[SchemaTypeKey]; exists && !matchesType(value, schemaType) {
+		return []ValidationIssue{{Path: path, Message: fmt.Sprintf(ValidationTypeErr, schemaType, jsonType(value))}}
+	}
+
+	issues := []ValidationIssue{}
+	if enum, ok := schema[SchemaEnumKey].([]interface{}); ok && !slices.ContainsFunc(enum, func(option interface{}) bool {
+		return reflect.DeepEqual(option, value)
+	}) {
+		issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(ValidationEnumErr, enum)})
+	}
+
+	switch typed := value.(type) {
+	case float64:
+		if minimum, ok := schema[SchemaMinimumKey].(float64); ok && typed < minimum {
+			issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(ValidationMinimumErr, minimum)})
+		}
+		if maximum, ok := schema[SchemaMaximumKey].(float64); ok && typed > maximum {
+			issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(ValidationMaximumErr, maximum)})
+		}
+	case []interface{}:
+		if minItems, ok := schema[SchemaMinItemsKey].(float64); ok && float64(len(typed)) < minItems {
+			issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(ValidationMinItemsErr, minItems)})
+		}
+		if maxItems, ok := schema[SchemaMaxItemsKey].(float64); ok && float64(len(typed)) > maxItems {
+			issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(ValidationMaxItemsErr, maxItems)})
+		}
+		if items, ok := schema[SchemaItemsKey].(map[string]interface{}); ok {
+			for i, item := range typed {
+				issues = append(issues, validateValue(item, items, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		issues = append(issues, validateObject(typed, schema, path)...)
+	}
+	return issues
+}
+
+func validateObject(object map[string]interface{}, schema map[string]interface{}, path string) []ValidationIssue {
+	// Validate the required fields and known properties of an object
+	issues := []ValidationIssue{}
+	if required, ok := schema[SchemaRequiredKey].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, exists := object[key]; !exists {
+					issues = append(issues, ValidationIssue{Path: propertyPath(path, key), Message: ValidationRequiredErr})
+				}
+			}
+		}
+	}
+
+	properties, _ := schema[SchemaPropertiesKey].(map[string]interface{})
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			issues = append(issues, validateValue(object[name], property, propertyPath(path, name))...)
+		}
+	}
+	return issues
+}
+
+func matchesType(value interface{}, schemaType interface{}) bool {
+	// Return if a value matches a schema type, or one of a list of types
+	if types, ok := schemaType.([]interface{}); ok {
+		return slices.ContainsFunc(types, func(option interface{}) bool {
+			return matchesType(value, option)
+		})
+	}
+
+	actual := jsonType(value)
+	switch schemaType {
+	case FunctionNumberTypeText:
+		return actual == FunctionNumberTypeText || actual == FunctionIntegerTypeText
+	default:
+		return actual == schemaType
+	}
+}
+
+func jsonType(value interface{}) string {
+	// Return the JSON schema type of a decoded value
+	switch typed := value.(type) {
+	case nil:
+		return FunctionNullTypeText
+	case bool:
+		return FunctionBooleanTypeText
+	case float64:
+		if typed == float64(int64(typed)) {
+			return FunctionIntegerTypeText
+		}
+		return FunctionNumberTypeText
+	case string:
+		return FunctionStringTypeText
+	case []interface{}:
+		return FunctionArrayTypeText
+	case map[string]interface{}:
+		return FunctionObjectTypeText
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func propertyPath(path string, name string) string {
+	// Join an object path and a property name
+	return path + "." + name
+}
@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"RTGPTGoCLI/pkg/logger"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+func NewClient(name string, server config.MCPServerConfig, defaultTimeout time.Duration) *Client {
+	// Create MCP client of a configured server
+	timeout := defaultTimeout
+	if server.Timeout > 0 {
+		timeout = time.Duration(server.Timeout) * time.Second
+	}
+
+	cmd := exec.Command(server.Command, server.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range server.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	return &Client{
+		name:    name,
+		cmd:     cmd,
+		timeout: timeout,
+		pending: make(map[int64]chan Response),
+		closed:  make(chan struct{}),
+		stderrDone: make(chan struct{}),
+	}
+}
+
+func (c *Client) Start(ctx context.Context) error {
+	// Launch the server and run the initialize handshake
+	logger.Debug(fmt.Sprintf(MCPStartingServerMsg, c.name, c.cmd.Path, c.cmd.Args[1:]))
+
+	stdin, err := c.cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := c.cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := c.cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := c.cmd.Start(); err != nil {
+		return err
+	}
+	c.stdin = stdin
+
+	go c.readRoutine(stdout)
+	go c.logRoutine(stderr)
+
+	initCtx, cancel := context.WithTimeout(ctx, MCPInitializeTimeout)
+	defer cancel()
+
+	params := InitializeParams{
+		ProtocolVersion: MCPProtocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo:      Implementation{Name: MCPClientName, Version: MCPClientVersion},
+	}
+	if err := c.call(initCtx, MCPInitializeMethod, params, nil); err != nil {
+		c.Close()
+		return err
+	}
+	return c.notify(MCPInitializedNotification, nil)
+}
+
+func (c *Client) ListTools(ctx context.Context) ([]ToolDefinition, error) {
+	// List every tool of the server, following pagination cursors
+	tools := []ToolDefinition{}
+	params := ListToolsParams{}
+	for {
+		var result ListToolsResult
+		if err := c.callWithTimeout(ctx, MCPToolsListMethod, params, &result); err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		params.Cursor = result.NextCursor
+	}
+}
+
+func (c *Client) CallTool(ctx context.Context, params CallToolParams) (*CallToolResult, error) {
+	// Call a tool of the server, bounded by the context of the call
+	var result CallToolResult
+	if err := c.call(ctx, MCPToolsCallMethod, params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) Close() error {
+	// Close the server stdin and stop the server
+	if c.stdin != nil {
+		c.stdin.Close()
+	}
+	if c.cmd.Process == nil {
+		return nil
+	}
+
+	select {
+	case <-c.closed:
+	case <-time.After(MCPCloseTimeout):
+		c.cmd.Process.Kill()
+	}
+	return nil
+}
+
+func (c *Client) callWithTimeout(ctx context.Context, method string, params interface{}, result interface{}) error {
+	// Send request bounded by the server timeout
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := c.call(callCtx, method, params, result)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf(MCPRequestTimeoutErr, method, c.timeout)
+	}
+	return err
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	// Send request and wait for its response
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	responseChannel := make(chan Response, 1)
+	c.pending[id] = responseChannel
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(Request{JSONRPC: MCPJSONRPCVersion, ID: &id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	select {
+	case response := <-responseChannel:
+		if response.Error != nil {
+			return fmt.Errorf(MCPRemoteErr, response.Error.Message, response.Error.Code)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(response.Result, result)
+	case <-c.closed:
+		return fmt.Errorf(MCPServerClosedErr, c.name)
+	case <-ctx.Done():
+		c.cancelRequest(id, method, ctx.Err())
+		return ctx.Err()
+	}
+}
+
+func (c *Client) cancelRequest(id int64, method string, reason error) {
+	// Tell the server to stop working on an abandoned request, the initialize request can't be cancelled
+	if method == MCPInitializeMethod {
+		return
+	}
+	if err := c.notify(MCPCancelledNotification, CancelledParams{RequestID: id, Reason: reason.Error()}); err != nil {
+		logger.Debug(fmt.Sprintf(MCPCancelRequestMsg, id, c.name, err))
+	}
+}
+
+func (c *Client) notify(method string, params interface{}) error {
+	// Send notification
+	return c.write(Request{JSONRPC: MCPJSONRPCVersion, Method: method, Params: params})
+}
+
+func (c *Client) write(request Request) error {
+	// Write newline delimited JSON-RPC message to the server
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (c *Client) readRoutine(stdout io.Reader) {
+	// Read server messages, routing responses to their pending requests
+	defer close(c.closed)
+	defer func() {
+		// Wait may only be called once both pipes are read to the end
+		<-c.stderrDone
+		c.cmd.Wait()
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), MCPMaxLineSize)
+	for scanner.Scan() {
+		var response Response
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil || response.ID == nil {
+			continue
+		}
+
+		c.mu.Lock()
+		responseChannel, exists := c.pending[*response.ID]
+		c.mu.Unlock()
+		if exists {
+			responseChannel <- response
+		}
+	}
+}
+
+func (c *Client) logRoutine(stderr io.Reader) {
+	// Forward server stderr to the debug log
+	defer close(c.stderrDone)
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		logger.Debug(fmt.Sprintf(MCPServerStderrMsg, c.name, scanner.Text()))
+	}
+
+	// Keep draining after a line too long for the scanner, so the server never blocks on stderr
+	io.Copy(io.Discard, stderr)
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"RTGPTGoCLI/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Stdio JSON-RPC server standing in for an MCP server, the tools drive its behavior
+const fakeServerSource = `package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type request struct {
+	ID     json.RawMessage ` + "`json:\"id\"`" + `
+	Method string          ` + "`json:\"method\"`" + `
+	Params json.RawMessage ` + "`json:\"params\"`" + `
+}
+
+func respond(id json.RawMessage, result interface{}) {
+	data, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": result})
+	fmt.Println(string(data))
+}
+
+func text(value string) []map[string]string {
+	return []map[string]string{{"type": "text", "text": value}}
+}
+
+func main() {
+	cancelled := []string{}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			continue
+		}
+		var params struct {
+			Cursor    string            ` + "`json:\"cursor\"`" + `
+			Name      string            ` + "`json:\"name\"`" + `
+			Arguments map[string]string ` + "`json:\"arguments\"`" + `
+			RequestID json.RawMessage   ` + "`json:\"requestId\"`" + `
+		}
+		json.Unmarshal(req.Params, &params)
+
+		switch req.Method {
+		case "initialize":
+			respond(req.ID, map[string]interface{}{"protocolVersion": "2025-06-18", "capabilities": map[string]interface{}{}, "serverInfo": map[string]string{"name": "fake", "version": "1"}})
+		case "notifications/cancelled":
+			cancelled = append(cancelled, string(params.RequestID))
+		case "tools/list":
+			switch {
+			case os.Getenv("FAKE_MCP_SLOW_LIST") != "":
+			case params.Cursor == "":
+				respond(req.ID, map[string]interface{}{"tools": []map[string]string{{"name": "echo"}, {"name": "fail"}}, "nextCursor": "page2"})
+			default:
+				respond(req.ID, map[string]interface{}{"tools": []map[string]string{{"name": "sleep"}, {"name": "die"}}})
+			}
+		case "tools/call":
+			switch params.Name {
+			case "echo":
+				respond(req.ID, map[string]interface{}{"content": text(params.Arguments["text"])})
+			case "fail":
+				respond(req.ID, map[string]interface{}{"content": text("boom"), "isError": true})
+			case "cancelled":
+				respond(req.ID, map[string]interface{}{"content": text(strings.Join(cancelled, ","))})
+			case "die":
+				os.Exit(1)
+			}
+		}
+	}
+}
+`
+
+func newFakeServer(t *testing.T, server config.MCPServerConfig) *Client {
+	// Build the fake server into a temp directory and start a client on it
+	dir := t.TempDir()
+	source := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(source, []byte(fakeServerSource), 0600); err != nil {
+		t.Fatalf("failed to write fake server: %v", err)
+	}
+	binary := filepath.Join(dir, "fake-mcp")
+	build := exec.Command("go", "build", "-o", binary, source)
+	build.Dir = dir
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build fake server: %v\n%s", err, output)
+	}
+
+	server.Command = binary
+	client := NewClient("fake", server, 5*time.Second)
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestListToolsFollowsPagination(t *testing.T) {
+	// Tools of every page are listed
+	client := newFakeServer(t, config.MCPServerConfig{})
+
+	tools, err := client.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	names := []string{}
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	if got := strings.Join(names, ","); got != "echo,fail,sleep,die" {
+		t.Errorf("ListTools() = %s, want echo,fail,sleep,die", got)
+	}
+}
+
+func TestListToolsServerTimeout(t *testing.T) {
+	// A request the server never answers fails after the server timeout
+	client := newFakeServer(t, config.MCPServerConfig{Timeout: 1, Env: map[string]string{"FAKE_MCP_SLOW_LIST": "1"}})
+
+	_, err := client.ListTools(context.Background())
+	if want := fmt.Sprintf(MCPRequestTimeoutErr, MCPToolsListMethod, time.Second); err == nil || err.Error() != want {
+		t.Errorf("ListTools() error = %v, want %s", err, want)
+	}
+}
+
+func TestCallTool(t *testing.T) {
+	// Tool results and tool errors are returned as results
+	client := newFakeServer(t, config.MCPServerConfig{})
+
+	tests := []struct {
+		name    string
+		params  CallToolParams
+		text    string
+		isError bool
+	}{
+		{"success", CallToolParams{Name: "echo", Arguments: map[string]interface{}{"text": "hello"}}, "hello", false},
+		{"tool error", CallToolParams{Name: "fail"}, "boom", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := client.CallTool(context.Background(), test.params)
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if len(result.Content) != 1 || result.Content[0].Text != test.text || result.IsError != test.isError {
+				t.Errorf("CallTool() = %+v, want text %q and isError %v", result, test.text, test.isError)
+			}
+		})
+	}
+}
+
+func TestCallToolTimeoutCancelsRequest(t *testing.T) {
+	// A call abandoned on its deadline is cancelled on the server
+	client := newFakeServer(t, config.MCPServerConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.CallTool(ctx, CallToolParams{Name: "sleep"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CallTool() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	client.mu.Lock()
+	sleepID := client.nextID
+	client.mu.Unlock()
+
+	result, err := client.CallTool(context.Background(), CallToolParams{Name: "cancelled"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if got, want := result.Content[0].Text, fmt.Sprint(sleepID); got != want {
+		t.Errorf("cancelled requests = %q, want %q", got, want)
+	}
+}
+
+func TestServerDiesMidRequest(t *testing.T) {
+	// A request pending when the server exits fails, and the client still closes
+	client := newFakeServer(t, config.MCPServerConfig{})
+
+	_, err := client.CallTool(context.Background(), CallToolParams{Name: "die"})
+	if want := fmt.Sprintf(MCPServerClosedErr, "fake"); err == nil || err.Error() != want {
+		t.Fatalf("CallTool() error = %v, want %s", err, want)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		client.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(MCPCloseTimeout):
+		t.Fatalf("Close() did not return after the server exited")
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import "time"
+
+const (
+	// MCP protocol constants
+	MCPProtocolVersion = "2025-06-18"
+	MCPJSONRPCVersion = "2.0"
+	MCPClientName = "rtgptcli"
+	MCPClientVersion = "1.0.0"
+	MCPServerName = "rtgptcli"
+	MCPServerVersion = "1.0.0"
+	MCPInitializeTimeout = 10 * time.Second
+	MCPCloseTimeout = 2 * time.Second
+	MCPMaxLineSize = 16 * 1024 * 1024
+)
+
+const (
+	// MCP methods
+	MCPInitializeMethod = "initialize"
+	MCPInitializedNotification = "notifications/initialized"
+	MCPCancelledNotification = "notifications/cancelled"
+	MCPToolsListMethod = "tools/list"
+	MCPToolsCallMethod = "tools/call"
+	MCPPingMethod = "ping"
+)
+
+const (
+	// JSON-RPC error codes
+	MCPParseErrorCode = -32700
+	MCPMethodNotFoundCode = -32601
+	MCPInvalidParamsCode = -32602
+)
+
+const (
+	// MCP content types
+	MCPTextContentType = "text"
+)
+
+const (
+	// Log messages
+	MCPStartingServerMsg = "Starting MCP server %s: %s %v"
+	MCPServerToolsMsg = "MCP server %s registered %d tools"
+	MCPServerStderrMsg = "MCP server %s: %s"
+	MCPCallingToolMsg = "Calling MCP tool %s on server %s with params: %+v"
+	MCPServingMsg = "Serving %d tools over MCP stdio"
+	MCPServerRequestMsg = "MCP request %s"
+	MCPCancelRequestMsg = "Failed to cancel request %d of MCP server %s: %v"
+)
+
+const (
+	// Errors
+	MCPStartServerErr = "failed to start MCP server %s: %v"
+	MCPInitializeErr = "failed to initialize MCP server %s: %v"
+	MCPListToolsErr = "failed to list tools of MCP server %s: %v"
+	MCPCallToolErr = "MCP tool %s failed: %v"
+	MCPToolReturnedErr = "MCP tool %s returned an error: %s"
+	MCPServerClosedErr = "MCP server %s closed the connection"
+	MCPRequestTimeoutErr = "MCP request %s timed out after %s"
+	MCPRemoteErr = "%s (code %d)"
+	MCPParseRequestErr = "failed to parse request: %v"
+	MCPMethodNotFoundErr = "method not found: %s"
+	MCPInvalidParamsErr = "invalid params: %v"
+)
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/logger"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+func NewServer(provider ToolProvider, in io.Reader, out io.Writer) *Server {
+	// Create MCP server publishing the tools of a provider
+	return &Server{
+		provider: provider,
+		in:       in,
+		out:      out,
+	}
+}
+
+func (s *Server) Serve(ctx context.Context) error {
+	// Serve newline delimited JSON-RPC requests until stdin closes or the context is done
+	logger.Debug(fmt.Sprintf(MCPServingMsg, len(s.provider.GetTools())))
+	defer s.wg.Wait()
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(s.in)
+		scanner.Buffer(make([]byte, 0, 64*1024), MCPMaxLineSize)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-scanErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			s.handleLine(ctx, line)
+		}
+	}
+}
+
+func (s *Server) handleLine(ctx context.Context, line []byte) {
+	// Dispatch a request, tool calls run concurrently
+	var request IncomingRequest
+	if err := json.Unmarshal(line, &request); err != nil {
+		s.writeError(json.RawMessage("null"), MCPParseErrorCode, fmt.Sprintf(MCPParseRequestErr, err))
+		return
+	}
+	logger.Debug(fmt.Sprintf(MCPServerRequestMsg, request.Method))
+
+	// Notifications have no id and get no response
+	if len(request.ID) == 0 {
+		return
+	}
+
+	switch request.Method {
+	case MCPInitializeMethod:
+		s.writeResult(request.ID, InitializeResult{
+			ProtocolVersion: MCPProtocolVersion,
+			Capabilities:    map[string]interface{}{"tools": map[string]interface{}{}},
+			ServerInfo:      Implementation{Name: MCPServerName, Version: MCPServerVersion},
+		})
+	case MCPPingMethod:
+		s.writeResult(request.ID, map[string]interface{}{})
+	case MCPToolsListMethod:
+		s.writeResult(request.ID, s.listTools())
+	case MCPToolsCallMethod:
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.callTool(ctx, request)
+		}()
+	default:
+		s.writeError(request.ID, MCPMethodNotFoundCode, fmt.Sprintf(MCPMethodNotFoundErr, request.Method))
+	}
+}
+
+func (s *Server) listTools() ListToolsResult {
+	// Convert the provider tools to MCP tool definitions
+	tools := s.provider.GetTools()
+	definitions := make([]ToolDefinition, 0, len(tools))
+	for _, tool := range tools {
+		definitions = append(definitions, ToolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.Parameters,
+		})
+	}
+	return ListToolsResult{Tools: definitions}
+}
+
+func (s *Server) callTool(ctx context.Context, request IncomingRequest) {
+	// Execute a tool, reporting its failures as tool errors the caller can read
+	var params ServerCallToolParams
+	if err := json.Unmarshal(request.Params, &params); err != nil || params.Name == "" {
+		s.writeError(request.ID, MCPInvalidParamsCode, fmt.Sprintf(MCPInvalidParamsErr, err))
+		return
+	}
+
+	arguments := string(params.Arguments)
+	if arguments == "" || arguments == "null" {
+		arguments = "{}"
+	}
+
+	result, appErr := s.provider.Execute(ctx, params.Name, arguments)
+	if appErr != nil {
+		text := appErr.Message
+		var argsErr *functions.ArgumentsError
+		if errors.As(appErr.Error, &argsErr) {
+			text = argsErr.Output()
+		}
+		s.writeResult(request.ID, CallToolResult{
+			Content: []Content{{Type: MCPTextContentType, Text: text}},
+			IsError: true,
+		})
+		return
+	}
+
+	if result == nil {
+		result = &functions.FunctionResult{}
+	}
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		s.writeResult(request.ID, CallToolResult{
+			Content: []Content{{Type: MCPTextContentType, Text: err.Error()}},
+			IsError: true,
+		})
+		return
+	}
+
+	// The summary is the readable text of the result, structured content carries object data
+	text := result.Summary
+	if text == "" {
+		text = string(data)
+	}
+	callResult := CallToolResult{Content: []Content{{Type: MCPTextContentType, Text: text}}}
+	if len(data) > 0 && data[0] == '{' {
+		callResult.StructuredContent = data
+	}
+	s.writeResult(request.ID, callResult)
+}
+
+func (s *Server) writeResult(id json.RawMessage, result interface{}) {
+	// Write a JSON-RPC result
+	s.write(OutgoingResponse{JSONRPC: MCPJSONRPCVersion, ID: id, Result: result})
+}
+
+func (s *Server) writeError(id json.RawMessage, code int, message string) {
+	// Write a JSON-RPC error
+	s.write(OutgoingResponse{JSONRPC: MCPJSONRPCVersion, ID: id, Error: &ResponseError{Code: code, Message: message}})
+}
+
+func (s *Server) write(response OutgoingResponse) {
+	// Write newline delimited JSON-RPC message to the client
+	data, err := json.Marshal(response)
+	if err != nil {
+		logger.Warning(err.Error())
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
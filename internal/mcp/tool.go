@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"RTGPTGoCLI/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func NewTools(client *Client, definitions []ToolDefinition) []*Tool {
+	// Wrap the tools of a server as functions
+	tools := make([]*Tool, 0, len(definitions))
+	for _, definition := range definitions {
+		if definition.InputSchema.Type == "" {
+			definition.InputSchema.Type = functions.FunctionObjectTypeText
+		}
+		if definition.InputSchema.Properties == nil {
+			definition.InputSchema.Properties = map[string]interface{}{}
+		}
+		tools = append(tools, &Tool{client: client, tool: definition})
+	}
+	return tools
+}
+
+func (t *Tool) Execute(ctx context.Context, params functions.FunctionParams) (*functions.FunctionResult, *errorhandler.AppError) {
+	// Route the call to tools/call of the server
+	name := t.tool.Name
+	logger.Debug(fmt.Sprintf(MCPCallingToolMsg, name, t.client.name, params))
+
+	if params == nil {
+		params = functions.FunctionParams{}
+	}
+	result, err := t.client.CallTool(ctx, CallToolParams{Name: name, Arguments: params})
+	if err != nil {
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(MCPCallToolErr, name, err), err)
+	}
+
+	text := result.text()
+	if result.IsError {
+		err := errors.New(text)
+		return nil, errorhandler.NewAppError(errorhandler.WarningLevel, fmt.Sprintf(MCPToolReturnedErr, name, text), err)
+	}
+
+	if len(result.StructuredContent) > 0 {
+		return functions.NewResult(result.StructuredContent, text), nil
+	}
+	return functions.NewResult(text, ""), nil
+}
+
+func (t *Tool) Timeout() time.Duration {
+	// Return the call timeout of the server
+	return t.client.timeout
+}
+
+func (t *Tool) GetMetadata() functions.FunctionPayload {
+	// Get tool metadata from its input schema
+	return functions.FunctionPayload{
+		Name:        t.tool.Name,
+		Description: t.tool.Description,
+		Parameters:  functions.ParametersFromSchema(t.tool.InputSchema),
+	}
+}
+
+func (t *Tool) ConvertToOpenAITool() functions.OpenAIToolsPayload {
+	// Convert tool to OpenAI tool
+	return functions.OpenAIToolsPayload{
+		Type:        functions.FunctionTypeText,
+		Name:        t.tool.Name,
+		Description: t.tool.Description,
+		Parameters:  t.tool.InputSchema,
+	}
+}
+
+func (result *CallToolResult) text() string {
+	// Join the text content blocks of a tool result
+	texts := []string{}
+	for _, content := range result.Content {
+		if content.Type == MCPTextContentType {
+			texts = append(texts, content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
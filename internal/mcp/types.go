@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"RTGPTGoCLI/internal/functions"
+	"RTGPTGoCLI/pkg/errorhandler"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+type Client struct {
+	// MCP client of a server launched over stdio
+	name    string
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	timeout time.Duration
+
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	nextID  int64
+	pending map[int64]chan Response
+	closed  chan struct{}
+	stderrDone chan struct{}
+}
+
+type Tool struct {
+	// MCP server tool registered as a function
+	client *Client
+	tool   ToolDefinition
+}
+
+type Request struct {
+	// JSON-RPC request or notification, notifications have no id
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int64      `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type Response struct {
+	// JSON-RPC response
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *ResponseError  `json:"error,omitempty"`
+}
+
+type ResponseError struct {
+	// JSON-RPC error
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type InitializeParams struct {
+	// initialize request params
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      Implementation         `json:"clientInfo"`
+}
+
+type Implementation struct {
+	// MCP client or server info
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type ListToolsParams struct {
+	// tools/list request params
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type ListToolsResult struct {
+	// tools/list result
+	Tools      []ToolDefinition `json:"tools"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+type ToolDefinition struct {
+	// MCP tool definition
+	Name        string                           `json:"name"`
+	Description string                           `json:"description,omitempty"`
+	InputSchema functions.ToolParametersMetadata `json:"inputSchema"`
+}
+
+type CallToolParams struct {
+	// tools/call request params
+	Name      string                   `json:"name"`
+	Arguments functions.FunctionParams `json:"arguments"`
+}
+
+type CancelledParams struct {
+	// notifications/cancelled params, sent when the client abandons a request
+	RequestID int64  `json:"requestId"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type CallToolResult struct {
+	// tools/call result
+	Content           []Content       `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+}
+
+type Content struct {
+	// MCP content block, only text blocks are used
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type ToolProvider interface {
+	// Functions published by the MCP server
+	GetTools() []functions.OpenAIToolsPayload
+	Execute(ctx context.Context, name string, argumentsJSON string) (*functions.FunctionResult, *errorhandler.AppError)
+}
+
+type Server struct {
+	// MCP server over stdio
+	provider ToolProvider
+	in       io.Reader
+	out      io.Writer
+	writeMu  sync.Mutex
+	wg       sync.WaitGroup
+}
+
+type InitializeResult struct {
+	// initialize result
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ServerInfo      Implementation         `json:"serverInfo"`
+}
+
+type IncomingRequest struct {
+	// JSON-RPC request received by the server, the id is kept verbatim
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type OutgoingResponse struct {
+	// JSON-RPC response sent by the server
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *ResponseError  `json:"error,omitempty"`
+}
+
+type ServerCallToolParams struct {
+	// tools/call params received by the server
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
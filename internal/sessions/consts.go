@@ -0,0 +1,40 @@
+package sessions
+
+import "os"
+
+const (
+	// Session store constants
+	SessionFileExtension = ".json"
+	SessionTempPattern = ".*.tmp"
+	SessionIDTimeLayout  = "20060102-150405"
+	SessionIDRandomBytes = 3
+	SessionTitleMaxLength = 60
+	SessionDirPermissions  os.FileMode = 0o700
+	SessionFilePermissions os.FileMode = 0o600
+)
+
+const (
+	// Session item constants
+	SessionMessageItemType = "message"
+	SessionFunctionCallItemType = "function_call"
+	SessionFunctionCallOutputItemType = "function_call_output"
+	SessionUserRole        = "user"
+	SessionUntitledText    = "(untitled)"
+)
+
+const (
+	// Usage texts
+	SessionUsageText = "%d tokens (%d input, %d cached, %d output)"
+	SessionCostText = "%.4f %s"
+	SessionUnpricedCostText = " + %d unpriced responses"
+)
+
+const (
+	// Errors
+	SessionCreateDirErr  = "failed to create sessions directory: %v"
+	SessionReadErr       = "failed to read session %s: %v"
+	SessionWriteErr      = "failed to write session %s: %v"
+	SessionNotFoundErr   = "session not found: %s"
+	SessionListErr       = "failed to list sessions: %v"
+	SessionInvalidIDErr  = "invalid session id: %s"
+)
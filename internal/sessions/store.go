@@ -0,0 +1,295 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func NewStore(dir string) *Store {
+	// Create session store under the given directory
+	return &Store{dir: dir}
+}
+
+func NewSession(model string) *Session {
+	// Create new empty session
+	now := time.Now()
+	return &Session{
+		ID:        newSessionID(now),
+		Model:     model,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Items:     []Item{},
+	}
+}
+
+func (store *Store) Save(session *Session) error {
+	// Save session to its file, replacing the previous version atomically, one save at a time so the latest version wins
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if err := os.MkdirAll(store.dir, SessionDirPermissions); err != nil {
+		return fmt.Errorf(SessionCreateDirErr, err)
+	}
+
+	jsonBytes, err := session.ToJSON()
+	if err != nil {
+		return fmt.Errorf(SessionWriteErr, session.ID, err)
+	}
+
+	path := store.sessionPath(session.ID)
+	tempFile, err := os.CreateTemp(store.dir, filepath.Base(path)+SessionTempPattern)
+	if err != nil {
+		return fmt.Errorf(SessionWriteErr, session.ID, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(jsonBytes)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), SessionFilePermissions)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf(SessionWriteErr, session.ID, err)
+	}
+	return nil
+}
+
+func (store *Store) Load(id string) (*Session, error) {
+	// Load session by its ID
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf(SessionInvalidIDErr, id)
+	}
+
+	jsonBytes, err := os.ReadFile(store.sessionPath(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf(SessionNotFoundErr, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(SessionReadErr, id, err)
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(jsonBytes, session); err != nil {
+		return nil, fmt.Errorf(SessionReadErr, id, err)
+	}
+	return session, nil
+}
+
+func (store *Store) List() ([]Summary, error) {
+	// List saved sessions, most recently updated first
+	entries, err := os.ReadDir(store.dir)
+	if os.IsNotExist(err) {
+		return []Summary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(SessionListErr, err)
+	}
+
+	summaries := []Summary{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != SessionFileExtension {
+			continue
+		}
+
+		session, err := store.Load(strings.TrimSuffix(entry.Name(), SessionFileExtension))
+		if err != nil {
+			continue
+		}
+		summaries = append(summaries, session.Summary())
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
+	})
+	return summaries, nil
+}
+
+func (store *Store) sessionPath(id string) string {
+	// Return session file path
+	return filepath.Join(store.dir, id+SessionFileExtension)
+}
+
+func (session *Session) UpsertItem(item Item) {
+	// Record a new item, or update an already recorded one keeping its timestamp
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	now := time.Now()
+	session.UpdatedAt = now
+
+	for i, existing := range session.Items {
+		if existing.ID == item.ID {
+			item.Timestamp = existing.Timestamp
+			session.Items[i] = item
+			return
+		}
+	}
+
+	if item.Timestamp.IsZero() {
+		item.Timestamp = now
+	}
+	session.Items = append(session.Items, item)
+
+	if session.Title == "" && item.Type == SessionMessageItemType && item.Role == SessionUserRole {
+		session.Title = truncateTitle(item.Text)
+	}
+}
+
+func (session *Session) ToJSON() ([]byte, error) {
+	// Marshal session to indented JSON
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return json.MarshalIndent(session, "", "  ")
+}
+
+func (session *Session) SetModel(model string) {
+	// Set the model the session continues with
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.Model = model
+}
+
+func (session *Session) AddResponseUsage(response ResponseUsage) {
+	// Record the usage of a response and add it to the session totals
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if response.Timestamp.IsZero() {
+		response.Timestamp = time.Now()
+	}
+	session.Responses = append(session.Responses, response)
+	session.Usage.Add(response)
+}
+
+func (session *Session) GetUsage() Usage {
+	// Return the token usage and cost totals of the session
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return session.Usage
+}
+
+func (session *Session) GetResponses() []ResponseUsage {
+	// Return a snapshot of the recorded response usages
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	responses := make([]ResponseUsage, len(session.Responses))
+	copy(responses, session.Responses)
+	return responses
+}
+
+func (usage *Usage) Add(response ResponseUsage) {
+	// Add the usage of a response to the totals
+	usage.Responses++
+	usage.InputTokens += response.InputTokens
+	usage.OutputTokens += response.OutputTokens
+	usage.CachedTokens += response.CachedTokens
+	usage.TotalTokens += response.TotalTokens
+	usage.Currency = response.Currency
+
+	if response.Cost == nil {
+		usage.UnpricedResponses++
+		return
+	}
+	usage.Cost += *response.Cost
+}
+
+func (usage *Usage) Merge(other Usage) {
+	// Add the totals of another usage to the totals
+	usage.Responses += other.Responses
+	usage.InputTokens += other.InputTokens
+	usage.OutputTokens += other.OutputTokens
+	usage.CachedTokens += other.CachedTokens
+	usage.TotalTokens += other.TotalTokens
+	usage.Cost += other.Cost
+	usage.UnpricedResponses += other.UnpricedResponses
+	if other.Currency != "" {
+		usage.Currency = other.Currency
+	}
+}
+
+func (usage Usage) TokensText() string {
+	// Describe the token totals in a single line
+	return fmt.Sprintf(SessionUsageText, usage.TotalTokens, usage.InputTokens, usage.CachedTokens, usage.OutputTokens)
+}
+
+func (usage Usage) CostText() string {
+	// Describe the cost total, mentioning responses of models without a price
+	costText := strings.TrimSpace(fmt.Sprintf(SessionCostText, usage.Cost, usage.Currency))
+	if usage.UnpricedResponses > 0 {
+		costText += fmt.Sprintf(SessionUnpricedCostText, usage.UnpricedResponses)
+	}
+	return costText
+}
+
+func (session *Session) GetItems() []Item {
+	// Return a snapshot of the recorded items
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	items := make([]Item, len(session.Items))
+	copy(items, session.Items)
+	return items
+}
+
+func (session *Session) TurnCount() int {
+	// Return the number of user turns in the session
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return countTurns(session.Items)
+}
+
+func (session *Session) Summary() Summary {
+	// Return session summary for listing
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+
+	title := session.Title
+	if title == "" {
+		title = SessionUntitledText
+	}
+	return Summary{
+		ID:        session.ID,
+		Title:     title,
+		UpdatedAt: session.UpdatedAt,
+		Turns:     countTurns(session.Items),
+	}
+}
+
+func newSessionID(now time.Time) string {
+	// Build a sortable, unique session ID from the creation time and random bytes
+	randomBytes := make([]byte, SessionIDRandomBytes)
+	rand.Read(randomBytes)
+	return now.Format(SessionIDTimeLayout) + "-" + hex.EncodeToString(randomBytes)
+}
+
+func countTurns(items []Item) int {
+	// Count user messages among the items
+	turns := 0
+	for _, item := range items {
+		if item.Type == SessionMessageItemType && item.Role == SessionUserRole {
+			turns++
+		}
+	}
+	return turns
+}
+
+func truncateTitle(text string) string {
+	// Build session title from the first user message
+	title := strings.Join(strings.Fields(text), " ")
+	runes := []rune(title)
+	if len(runes) > SessionTitleMaxLength {
+		return string(runes[:SessionTitleMaxLength]) + "..."
+	}
+	return title
+}
@@ -0,0 +1,71 @@
+package sessions
+
+import (
+	"sync"
+	"time"
+)
+
+type Store struct {
+	// Session store struct, keeping one file per session under a directory
+	mu  sync.Mutex
+	dir string
+}
+
+type Session struct {
+	// Chat session struct, recording every user, assistant and tool item
+	mu        sync.RWMutex
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Items     []Item    `json:"items"`
+	Usage     Usage     `json:"usage"`
+	Responses []ResponseUsage `json:"responses,omitempty"`
+}
+
+type Item struct {
+	// Session item struct (message, function_call or function_call_output)
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Role      string    `json:"role,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	CallID    string    `json:"call_id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Arguments string    `json:"arguments,omitempty"`
+	Output    string    `json:"output,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type Usage struct {
+	// Token usage and cost summed over responses, responses of models without a price add no cost
+	Responses         int     `json:"responses"`
+	InputTokens       int     `json:"input_tokens"`
+	OutputTokens      int     `json:"output_tokens"`
+	CachedTokens      int     `json:"cached_tokens"`
+	TotalTokens       int     `json:"total_tokens"`
+	Cost              float64 `json:"cost"`
+	Currency          string  `json:"currency,omitempty"`
+	UnpricedResponses int     `json:"unpriced_responses,omitempty"`
+}
+
+type ResponseUsage struct {
+	// Token usage and cost of a single response, without a cost when its model has no price
+	ResponseID   string    `json:"response_id"`
+	Model        string    `json:"model"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	CachedTokens int       `json:"cached_tokens"`
+	TotalTokens  int       `json:"total_tokens"`
+	Cost         *float64  `json:"cost,omitempty"`
+	Currency     string    `json:"currency,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+type Summary struct {
+	// Session summary struct, used for listing
+	ID        string
+	Title     string
+	UpdatedAt time.Time
+	Turns     int
+}
@@ -6,9 +6,7 @@ import (
 )
 
 func NewErrorHandler(debug bool) *ErrorHandler {
-	// Create error handler
-	logger.InitLoggers()
-	logger.SetDebugMode(debug)
+	// Create error handler, the loggers and their debug mode are set up once by main
 	return &ErrorHandler{
 		debug: debug,
 	}
@@ -31,6 +29,9 @@ func (eh *ErrorHandler) HandleError(appErr AppError) {
 }
 
 func (eh *ErrorHandler) getErrorString(appErr AppError) string {
-	// Get app error string
+	// Get app error string, the wrapped error is optional
+	if appErr.Error == nil {
+		return appErr.Message
+	}
 	return appErr.Message + "\n" + appErr.Error.Error()
 }
@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"RTGPTGoCLI/pkg/redact"
+	"io"
 	"log"
 	"os"
 )
@@ -14,6 +16,12 @@ func InitLoggers() {
 	isDebugMode = false
 }
 
+func SetOutput(w io.Writer) {
+	// Redirect info and debug logs, which default to stdout
+	infoLogger.SetOutput(w)
+	debugLogger.SetOutput(w)
+}
+
 func SetDebugMode(debug bool) {
 	// Set debug mode
 	isDebugMode = debug
@@ -21,24 +29,24 @@ func SetDebugMode(debug bool) {
 
 func Info(msg string) {
 	// Print info log
-	infoLogger.Println(msg)
+	infoLogger.Println(redact.String(msg))
 }
 
 func Debug(msg string) {
 	// Print debug log, only if debug mode is enabled
 	if isDebugMode {
-		debugLogger.Println(msg)
+		debugLogger.Println(redact.String(msg))
 	}
 }
 
 func Warning(msg string) {
 	// Print warning log, only if debug mode is enabled
 	if isDebugMode {
-		warningLogger.Println(msg)
+		warningLogger.Println(redact.String(msg))
 	}
 }
 
 func Error(msg string) {
 	// Print error log
-	errorLogger.Println(msg)
+	errorLogger.Println(redact.String(msg))
 }
@@ -0,0 +1,30 @@
+package redact
+
+import "regexp"
+
+// Redaction constants
+const (
+	MASK = "[REDACTED]"
+	MIN_SECRET_LENGTH = 4
+)
+
+// Normalized names of fields holding secrets, compared without case, "_" and "-"
+var SECRET_FIELDS = map[string]bool{
+	"apikey":        true,
+	"authorization": true,
+	"token":         true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"secret":        true,
+	"clientsecret":  true,
+	"password":      true,
+}
+
+// Suffixes of normalized field names holding secrets, such as GITHUB_TOKEN
+var SECRET_FIELD_SUFFIXES = []string{"token", "secret", "password", "apikey"}
+
+// Patterns of values that look like tokens
+var TOKEN_PATTERNS = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`\bsk-[A-Za-z0-9\-_]{8,}`),
+}
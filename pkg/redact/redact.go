@@ -0,0 +1,100 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+func AddSecret(values ...string) {
+	// Register secret values that must be masked wherever they appear
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range values {
+		if len(value) >= MIN_SECRET_LENGTH {
+			secrets = append(secrets, value)
+		}
+	}
+}
+
+func String(text string) string {
+	// Mask registered secrets and token-like values in text
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, MASK)
+	}
+	secretsMu.RUnlock()
+
+	text = TOKEN_PATTERNS[0].ReplaceAllString(text, "${1}"+MASK)
+	for _, pattern := range TOKEN_PATTERNS[1:] {
+		text = pattern.ReplaceAllString(text, MASK)
+	}
+	return text
+}
+
+func Value(value interface{}) interface{} {
+	// Mask secret fields and token-like strings of a decoded JSON value
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(typed))
+		for key, fieldValue := range typed {
+			if isSecretField(key) && fieldValue != nil && fieldValue != "" {
+				masked[key] = MASK
+				continue
+			}
+			masked[key] = Value(fieldValue)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(typed))
+		for i, item := range typed {
+			masked[i] = Value(item)
+		}
+		return masked
+	case string:
+		return String(typed)
+	default:
+		return value
+	}
+}
+
+func JSON(data []byte) []byte {
+	// Mask secret fields of JSON data, falling back to plain text masking
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return []byte(String(string(data)))
+	}
+
+	masked, err := json.Marshal(Value(decoded))
+	if err != nil {
+		return []byte(String(string(data)))
+	}
+	return masked
+}
+
+func isSecretField(name string) bool {
+	// Return if a field name holds a secret
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	if SECRET_FIELDS[normalized] {
+		return true
+	}
+	for _, suffix := range SECRET_FIELD_SUFFIXES {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func MarshalIndent(value interface{}) ([]byte, error) {
+	// Marshal a value to indented JSON with secret fields masked
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(Value(decoded), "", "  ")
+}
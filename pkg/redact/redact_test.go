@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	// Mask registered secrets and token-like values, keeping the rest of the text
+	AddSecret("registered-secret-value", "abc")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"bearer token", "Authorization: Bearer abc.DEF-123_456", "Authorization: Bearer " + MASK},
+		{"lowercase bearer token", "authorization: bearer abc123==", "authorization: bearer " + MASK},
+		{"sk token", "using key sk-abcdefgh12345 now", "using key " + MASK + " now"},
+		{"project sk token", "sk-proj-AbC_dEf-123456789", MASK},
+		{"short sk value", "sk-short", "sk-short"},
+		{"registered secret", "the key is registered-secret-value.", "the key is " + MASK + "."},
+		{"too short secret is not registered", "abc", "abc"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := String(test.input); got != test.want {
+				t.Errorf("String(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	// Mask secret fields of JSON data, falling back to text masking for invalid JSON
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"secret field", `{"api_key":"abc123","model":"gpt"}`, `{"api_key":"` + MASK + `","model":"gpt"}`},
+		{"secret field name variants", `{"API-KEY":"a","apiKey":"b","Password":"c"}`, `{"API-KEY":"` + MASK + `","Password":"` + MASK + `","apiKey":"` + MASK + `"}`},
+		{"secret suffixed fields", `{"GITHUB_TOKEN":"a","client_secret":"b","db_password":"c"}`, `{"GITHUB_TOKEN":"` + MASK + `","client_secret":"` + MASK + `","db_password":"` + MASK + `"}`},
+		{"nested authorization header", `{"headers":{"Authorization":"Bearer xyz"}}`, `{"headers":{"Authorization":"` + MASK + `"}}`},
+		{"token in array", `{"items":["ok","sk-abcdefgh12345"]}`, `{"items":["ok","` + MASK + `"]}`},
+		{"token in text field", `{"text":"use Bearer abc123"}`, `{"text":"use Bearer ` + MASK + `"}`},
+		{"empty secret field", `{"token":""}`, `{"token":""}`},
+		{"token counts are kept", `{"total_tokens":42,"max_tokens":"inf"}`, `{"max_tokens":"inf","total_tokens":42}`},
+		{"invalid json", `not json sk-abcdefgh12345`, `not json ` + MASK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(JSON([]byte(test.input))); got != test.want {
+				t.Errorf("JSON(%s) = %s, want %s", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMarshalIndent(t *testing.T) {
+	// Marshal values with secret fields and token-like strings masked
+	type settings struct {
+		APIKey  string
+		Model   string
+		Headers map[string]string
+		Env     map[string]string
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		contains []string
+		secrets  []string
+	}{
+		{
+			name: "struct fields",
+			value: settings{
+				APIKey:  "sk-abcdefgh12345",
+				Model:   "gpt-4o",
+				Headers: map[string]string{"Authorization": "Bearer xyz", "Accept": "application/json"},
+				Env:     map[string]string{"GITHUB_TOKEN": "ghp_123456", "HOME": "/home/user"},
+			},
+			contains: []string{`"APIKey": "` + MASK + `"`, `"Model": "gpt-4o"`, `"Accept": "application/json"`, `"HOME": "/home/user"`},
+			secrets:  []string{"sk-abcdefgh12345", "xyz", "ghp_123456"},
+		},
+		{
+			name:     "token-like values of plain fields",
+			value:    map[string]interface{}{"note": "key sk-abcdefgh12345", "count": 3},
+			contains: []string{`"note": "key ` + MASK + `"`, `"count": 3`},
+			secrets:  []string{"sk-abcdefgh12345"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := MarshalIndent(test.value)
+			if err != nil {
+				t.Fatalf("MarshalIndent() error = %v", err)
+			}
+			got := string(data)
+			for _, want := range test.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("MarshalIndent() = %s, want it to contain %s", got, want)
+				}
+			}
+			for _, secret := range test.secrets {
+				if strings.Contains(got, secret) {
+					t.Errorf("MarshalIndent() = %s, leaks %s", got, secret)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package redact
+
+import "sync"
+
+// Redaction variables
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)